@@ -1,9 +1,28 @@
 package mongo
 
+import "fmt"
+
 // assertSameMoneyCurrency will panic if the arguments are money objects
 // containing different currencies.
 func assertSameMoneyCurrency(a, b Money) {
-	if a.format.code != b.format.code {
+	if checkSameMoneyCurrency(a, b) != nil {
+		panic("Failed to perform operation on different currencies")
+	}
+}
+
+// checkSameMoneyCurrency will return an error wrapping ErrCurrencyMismatch if
+// the arguments are money objects containing different currencies.
+func checkSameMoneyCurrency(a, b Money) error {
+	if a.currency.Code != b.currency.Code {
+		return fmt.Errorf("%w: '%s' and '%s'", ErrCurrencyMismatch, a.currency.Code, b.currency.Code)
+	}
+	return nil
+}
+
+// assertSameBigMoneyCurrency will panic if the arguments are big money objects
+// containing different currencies.
+func assertSameBigMoneyCurrency(a, b BigMoney) {
+	if a.currency.Code != b.currency.Code {
 		panic("Failed to perform operation on different currencies")
 	}
 }
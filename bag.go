@@ -0,0 +1,158 @@
+package mongo
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Bag is a collection of monetary values in different currencies, holding one
+// money object per currency. It's useful for totalling items that may not
+// share a currency, e.g. a shopping cart. Bags are immutable, every operation
+// returns a new bag. Currencies with a zero value are not held.
+type Bag struct {
+	values map[string]Money
+}
+
+// NewBag constructs a new bag containing the sum of the passed money objects.
+func NewBag(m ...Money) Bag {
+	return Bag{}.Add(m...)
+}
+
+// Add is an arithmetic operator that adds money of any currency to the bag.
+func (b Bag) Add(m ...Money) Bag {
+	r, _ := b.apply(m, func(x, y Money) (Money, error) { return x.Add(y), nil })
+	return r
+}
+
+// Sub is an arithmetic operator that subtracts money of any currency from the
+// bag.
+func (b Bag) Sub(m ...Money) Bag {
+	r, _ := b.apply(m, func(x, y Money) (Money, error) { return x.Sub(y), nil })
+	return r
+}
+
+// AddBag is an arithmetic operator that adds the contents of another bag.
+func (b Bag) AddBag(v Bag) Bag {
+	return b.Add(v.Values()...)
+}
+
+// SubBag is an arithmetic operator that subtracts the contents of another bag.
+func (b Bag) SubBag(v Bag) Bag {
+	return b.Sub(v.Values()...)
+}
+
+// Get returns the money held in the passed currency and true, or false if
+// none is held.
+func (b Bag) Get(code string) (Money, bool) {
+	m, ok := b.values[code]
+	return m, ok
+}
+
+// Currencies returns the ISO 4217 codes of the currencies held, sorted.
+func (b Bag) Currencies() []string {
+	codes := make([]string, 0, len(b.values))
+	for code := range b.values {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Values returns the money held sorted by currency code.
+func (b Bag) Values() []Money {
+	s := make([]Money, 0, len(b.values))
+	for _, code := range b.Currencies() {
+		s = append(s, b.values[code])
+	}
+	return s
+}
+
+// Len returns the number of currencies held.
+func (b Bag) Len() int {
+	return len(b.values)
+}
+
+// IsZero returns a boolean value if the bag holds no money.
+func (b Bag) IsZero() bool {
+	return len(b.values) == 0
+}
+
+// Total converts the money held to a single currency using rates supplied by
+// the provider and returns the sum. Each value is converted separately using
+// its own rounding function.
+func (b Bag) Total(to string, provider RateProvider) (Money, error) {
+	total, err := MoneyFromSubunits(to, 0, nil)
+	if err != nil {
+		return Money{}, fmt.Errorf("failed to total money, %w", err)
+	}
+	for _, m := range b.Values() {
+		c, err := Convert(m, to, provider)
+		if err != nil {
+			return Money{}, fmt.Errorf("failed to total money, %w", err)
+		}
+		total, err = total.AddE(c)
+		if err != nil {
+			return Money{}, fmt.Errorf("failed to total money, %w", err)
+		}
+	}
+	return total, nil
+}
+
+// MarshalJSON is an implementation of json.Marshaller. The bag is encoded as
+// an array of money objects sorted by currency code.
+func (b Bag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.Values())
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. Money objects with
+// the same currency are summed.
+func (b *Bag) UnmarshalJSON(data []byte) error {
+	var s []Money
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("failed to unmarshal bag, %w", err)
+	}
+	result, err := Bag{}.AddE(s...)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal bag, %w", err)
+	}
+	*b = result
+	return nil
+}
+
+// String is an implementation of fmt.Stringer and returns the formatted money
+// held, sorted by currency code and separated by commas.
+func (b Bag) String() string {
+	s := make([]string, 0, len(b.values))
+	for _, m := range b.Values() {
+		s = append(s, m.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// apply returns a new bag with the operator applied to the money of each
+// currency, or the first error returned by the operator.
+func (b Bag) apply(s []Money, op func(Money, Money) (Money, error)) (Bag, error) {
+	values := make(map[string]Money, len(b.values)+len(s))
+	for code, m := range b.values {
+		values[code] = m
+	}
+	for _, m := range s {
+		code := m.IsoCode()
+		current, ok := values[code]
+		if !ok {
+			current = m.Clone(0)
+		}
+		current, err := op(current, m)
+		if err != nil {
+			return Bag{}, err
+		}
+		if current.IsZero() {
+			delete(values, code)
+		} else {
+			values[code] = current
+		}
+	}
+	return Bag{values: values}, nil
+}
@@ -0,0 +1,134 @@
+package mongo
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestBag(t *testing.T) {
+	gbp, _ := MoneyGBP(1055)
+	eur, _ := MoneyEUR(300)
+	usd, _ := MoneyUSD(199)
+
+	b := NewBag(gbp, eur)
+	assert(t, b.Len() == 2)
+	assert(t, !b.IsZero())
+	assert(t, b.String() == "€3.00, £10.55")
+
+	c := b.Add(gbp, usd)
+	assert(t, c.String() == "€3.00, £21.10, $1.99")
+	assert(t, b.String() == "€3.00, £10.55")
+
+	m, ok := c.Get("GBP")
+	assert(t, ok)
+	assertMoneyValue(t, m, 2110)
+
+	_, ok = c.Get("JPY")
+	assert(t, !ok)
+
+	c = c.Sub(eur)
+	assert(t, c.Len() == 2)
+	assert(t, c.Currencies()[0] == "GBP" && c.Currencies()[1] == "USD")
+
+	c = c.Sub(gbp, gbp, gbp)
+	m, _ = c.Get("GBP")
+	assertMoneyValue(t, m, -1055)
+
+	c = c.SubBag(c)
+	assert(t, c.IsZero())
+	assert(t, c.String() == "")
+
+	c = Bag{}.AddBag(b)
+	assert(t, c.String() == b.String())
+
+	var zero Bag
+	assert(t, zero.IsZero() && zero.Len() == 0)
+	_, ok = zero.Get("GBP")
+	assert(t, !ok)
+}
+
+func TestBagChecked(t *testing.T) {
+	max, _ := MoneyGBP(math.MaxInt64)
+	min, _ := MoneyGBP(math.MinInt64)
+	gbp, _ := MoneyGBP(2)
+	eur, _ := MoneyEUR(300)
+
+	b, err := NewBag(gbp).AddE(eur, gbp)
+	assert(t, err == nil)
+	assert(t, b.String() == "€3.00, £0.04")
+
+	b, err = b.SubBagE(b)
+	assert(t, err == nil)
+	assert(t, b.IsZero())
+
+	_, err = NewBag(max).AddE(gbp)
+	assert(t, errors.Is(err, ErrOverflow))
+	_, err = NewBag(max).AddBagE(NewBag(max))
+	assert(t, errors.Is(err, ErrOverflow))
+	_, err = NewBag(min).SubE(gbp)
+	assert(t, errors.Is(err, ErrOverflow))
+	_, err = NewBag(min).SubBagE(NewBag(max))
+	assert(t, errors.Is(err, ErrOverflow))
+
+	var bag Bag
+	err = json.Unmarshal([]byte(`[{"currency":"GBP","amount":9223372036854775807},{"currency":"GBP","amount":1}]`), &bag)
+	assert(t, errors.Is(err, ErrOverflow))
+}
+
+func TestBagKeepsRounding(t *testing.T) {
+	m, _ := MoneyFromSubunits("GBP", 1000, RoundDown)
+	b := NewBag(m)
+	m, _ = b.Get("GBP")
+	assertMoneyValue(t, m.Div(3), 333)
+	assertMoneyValue(t, m.Mul(-1).Div(3), -334)
+}
+
+func TestBagTotal(t *testing.T) {
+	p, _ := NewMemoryRates(
+		ExchangeRate{Base: "GBP", Quote: "EUR", Rate: big.NewRat(12, 10)},
+		ExchangeRate{Base: "USD", Quote: "EUR", Rate: big.NewRat(9, 10)},
+	)
+	gbp, _ := MoneyGBP(1000)
+	eur, _ := MoneyEUR(300)
+	usd, _ := MoneyUSD(200)
+
+	total, err := NewBag(gbp, eur, usd).Total("EUR", p)
+	assert(t, err == nil)
+	assertMoneyString(t, total, "EUR", "€16.80")
+
+	total, err = Bag{}.Total("EUR", p)
+	assert(t, err == nil)
+	assertMoneyValue(t, total, 0)
+
+	jpy, _ := MoneyFromSubunits("JPY", 100, nil)
+	_, err = NewBag(jpy).Total("EUR", p)
+	assert(t, errors.Is(err, ErrNoRate))
+
+	_, err = NewBag(gbp).Total("XXX", p)
+	assert(t, err != nil)
+}
+
+func TestBagJSON(t *testing.T) {
+	gbp, _ := MoneyGBP(1055)
+	eur, _ := MoneyEUR(300)
+
+	b, err := json.Marshal(NewBag(gbp, eur))
+	assert(t, err == nil)
+	assertJSON(t, b, `[{"currency":"EUR","amount":"€3.00"},{"currency":"GBP","amount":"£10.55"}]`)
+
+	var bag Bag
+	err = json.Unmarshal([]byte(`[{"currency":"GBP","amount":"£10.55"},{"currency":"EUR","amount":300},{"currency":"GBP","amount":"£1.00"}]`), &bag)
+	assert(t, err == nil)
+	assert(t, bag.String() == "€3.00, £11.55")
+
+	b, _ = json.Marshal(Bag{})
+	assertJSON(t, b, `[]`)
+
+	err = json.Unmarshal([]byte(`{"currency":"GBP"}`), &bag)
+	assert(t, err != nil)
+	err = json.Unmarshal([]byte(`[{"currency":"XXX","amount":1}]`), &bag)
+	assert(t, err != nil)
+}
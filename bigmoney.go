@@ -0,0 +1,347 @@
+package mongo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// BigMoney is an arbitrary precision version of Money for monetary values that
+// can't be represented by an int64 of subunits, e.g. hyperinflation currencies
+// or aggregated national totals. It supports the same operations as Money.
+type BigMoney struct {
+	currency Currency     // The currency definition.
+	value    *big.Int     // The monetary value as a integer. This is never modified once set.
+	round    RoundingMode // The rounding mode to use for division and multiplication.
+}
+
+// BigMoneyFromSubunits constructs a new big money object from an integer. The
+// integer used should represent the subunits of the currency.
+// currIsoCode is an ISO 4217 currency code.
+// value is monetary value in subunits.
+// f is the rounding mode to be used for division operations.
+func BigMoneyFromSubunits(currIsoCode string, value *big.Int, f RoundingMode) (BigMoney, error) {
+	curr, err := lookupCurrency(currIsoCode)
+	if err != nil {
+		return BigMoney{}, err
+	}
+	if f == nil {
+		f = RoundHalfUp
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	b := BigMoney{
+		currency: curr,
+		value:    new(big.Int).Set(value),
+		round:    f,
+	}
+	return b, nil
+}
+
+// BigMoneyFromString constructs a new big money object from a string.
+// Everything not contained within a number is stripped out before parsing.
+// currIsoCode is an ISO 4217 currency code.
+// str is monetary value expressed as a string.
+// f is the rounding mode to be used for division operations.
+func BigMoneyFromString(currIsoCode string, str string, f RoundingMode) (BigMoney, error) {
+	curr, err := lookupCurrency(currIsoCode)
+	if err != nil {
+		return BigMoney{}, err
+	}
+
+	str, err = parseSubunits(curr, str)
+	if err != nil {
+		return BigMoney{}, err
+	}
+
+	value, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return BigMoney{}, fmt.Errorf("failed to parse string to money, '%s' is not a number", str)
+	}
+
+	return BigMoneyFromSubunits(currIsoCode, value, f)
+}
+
+// Big converts the money object to a big money object.
+func (m Money) Big() BigMoney {
+	return BigMoney{
+		currency: m.currency,
+		value:    big.NewInt(m.value),
+		round:    m.round,
+	}
+}
+
+// Money converts the big money object to a money object. An error wrapping
+// ErrOverflow is returned if the value doesn't fit.
+func (b BigMoney) Money() (Money, error) {
+	if !b.int().IsInt64() {
+		return Money{}, fmt.Errorf("failed to convert %s to money, %w", b, ErrOverflow)
+	}
+	m := Money{
+		currency: b.currency,
+		value:    b.int().Int64(),
+		round:    b.round,
+	}
+	return m, nil
+}
+
+// Clone returns a copy of big money with a different value.
+func (b BigMoney) Clone(value *big.Int) BigMoney {
+	b.value = new(big.Int).Set(value)
+	return b
+}
+
+// Currency returns the definition of the currency the money was created with.
+func (b BigMoney) Currency() Currency {
+	return b.currency
+}
+
+// Rounding returns the rounding mode used for division and multiplication.
+func (b BigMoney) Rounding() RoundingMode {
+	if b.round == nil {
+		return RoundHalfUp
+	}
+	return b.round
+}
+
+// IsoCode returns the ISO 4217 currency code.
+func (b BigMoney) IsoCode() string {
+	return b.currency.Code
+}
+
+// Value returns a copy of the entire monetary value expressed in subunits.
+func (b BigMoney) Value() *big.Int {
+	return new(big.Int).Set(b.int())
+}
+
+// Add is an arithmetic operator.
+func (b BigMoney) Add(v BigMoney) BigMoney {
+	assertSameBigMoneyCurrency(b, v)
+	b.value = new(big.Int).Add(b.int(), v.int())
+	return b
+}
+
+// Sub is an arithmetic operator.
+func (b BigMoney) Sub(v BigMoney) BigMoney {
+	assertSameBigMoneyCurrency(b, v)
+	b.value = new(big.Int).Sub(b.int(), v.int())
+	return b
+}
+
+// Mul is an arithmetic operator.
+func (b BigMoney) Mul(n int64) BigMoney {
+	b.value = new(big.Int).Mul(b.int(), big.NewInt(n))
+	return b
+}
+
+// Abs returns a big money object with an absolute value.
+func (b BigMoney) Abs() BigMoney {
+	b.value = new(big.Int).Abs(b.int())
+	return b
+}
+
+// FlipSign flips the sign of the big money object's value. Switching positive
+// to negative and vice versa.
+func (b BigMoney) FlipSign() BigMoney {
+	b.value = new(big.Int).Neg(b.int())
+	return b
+}
+
+// Cmp is a logical operator. It returns -1 if the money is less than v, 0 if
+// they are equal and 1 if it's greater than v.
+func (b BigMoney) Cmp(v BigMoney) int {
+	assertSameBigMoneyCurrency(b, v)
+	return b.int().Cmp(v.int())
+}
+
+// Eq is a logical operator.
+func (b BigMoney) Eq(v BigMoney) bool {
+	return b.Cmp(v) == 0
+}
+
+// Neq is a logical operator.
+func (b BigMoney) Neq(v BigMoney) bool {
+	return b.Cmp(v) != 0
+}
+
+// Gt is a logical operator.
+func (b BigMoney) Gt(v BigMoney) bool {
+	return b.Cmp(v) > 0
+}
+
+// Gte is a logical operator.
+func (b BigMoney) Gte(v BigMoney) bool {
+	return b.Cmp(v) >= 0
+}
+
+// Lt is a logical operator.
+func (b BigMoney) Lt(v BigMoney) bool {
+	return b.Cmp(v) < 0
+}
+
+// Lte is a logical operator.
+func (b BigMoney) Lte(v BigMoney) bool {
+	return b.Cmp(v) <= 0
+}
+
+// IsZero returns a boolean value if the value is zero.
+func (b BigMoney) IsZero() bool {
+	return b.int().Sign() == 0
+}
+
+// IsPos returns a boolean value if the value is positive.
+func (b BigMoney) IsPos() bool {
+	return b.int().Sign() >= 0
+}
+
+// IsNeg returns a boolean value if the value is negative.
+func (b BigMoney) IsNeg() bool {
+	return b.int().Sign() < 0
+}
+
+// Split returns a slice containing big money objects split as evenly as
+// possible by 'n' times. This operation is lossless and will account for all
+// remainders.
+func (b BigMoney) Split(n int64) []BigMoney {
+	if n <= 0 {
+		panic("Failed to split money by zero")
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return b.Allocate(ratios...)
+}
+
+// Allocate returns a slice containing big money objects split according to the
+// passed ratios. The ratios are completely arbitrary and are calculated as
+// percentages of the overall sum. This operation is lossless and will account
+// for all remainders.
+func (b BigMoney) Allocate(ratios ...int64) []BigMoney {
+	sum := new(big.Int)
+	for _, n := range ratios {
+		sum.Add(sum, big.NewInt(n))
+	}
+	if sum.Sign() <= 0 {
+		panic("Failed to allocate money, no ratios passed")
+	}
+	s := make([]BigMoney, 0, len(ratios))
+	allocated := new(big.Int)
+	for _, n := range ratios {
+		value := new(big.Int).Mul(b.int(), big.NewInt(n))
+		value.Quo(value, sum)
+		s = append(s, b.Clone(value))
+		allocated.Add(allocated, value)
+	}
+	rem := new(big.Int).Sub(b.int(), allocated).Int64()
+	one := big.NewInt(1)
+	for i := 0; i < len(s) && rem > 0; i++ {
+		s[i].value.Add(s[i].value, one)
+		rem--
+	}
+	for i := 0; i < len(s) && rem < 0; i++ {
+		s[i].value.Sub(s[i].value, one)
+		rem++
+	}
+	return s
+}
+
+// MarshalJSON is an implementation of json.Marshaller. The rounding mode is
+// included the same way as Money.
+func (b BigMoney) MarshalJSON() ([]byte, error) {
+	json := fmt.Sprintf(`{"currency": "%s", "amount":"%s"%s}`, b.IsoCode(), b.String(), b.round.jsonField())
+	return []byte(json), nil
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. It accepts the same
+// formats as Money, although an integer amount of subunits may be of any size.
+func (b *BigMoney) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var v struct {
+		Currency string          `json:"currency"`
+		Amount   json.RawMessage `json:"amount"`
+		Rounding *RoundingMode   `json:"rounding"`
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		if b.currency.Code == "" {
+			return fmt.Errorf("failed to unmarshal money, no currency specified for amount %s", data)
+		}
+		v.Currency = b.currency.Code
+		v.Amount = data
+		v.Rounding = &b.round
+	} else if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal money, %w", err)
+	}
+
+	if v.Currency == "" {
+		return fmt.Errorf("failed to unmarshal money, no currency specified")
+	}
+	if _, err := lookupCurrency(v.Currency); err != nil {
+		return fmt.Errorf("failed to unmarshal money, %w", err)
+	}
+	if len(v.Amount) == 0 {
+		return fmt.Errorf("failed to unmarshal money, no amount specified")
+	}
+
+	round := RoundingMode(RoundHalfUp)
+	if v.Rounding != nil {
+		round = *v.Rounding
+	}
+
+	var parsed BigMoney
+	var err error
+
+	switch v.Amount[0] {
+	case '"':
+		var str string
+		if err = json.Unmarshal(v.Amount, &str); err != nil {
+			return fmt.Errorf("failed to unmarshal money, %w", err)
+		}
+		parsed, err = BigMoneyFromString(v.Currency, str, round)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal money, invalid amount '%s': %w", str, err)
+		}
+	default:
+		value, ok := new(big.Int).SetString(string(v.Amount), 10)
+		if !ok {
+			return fmt.Errorf("failed to unmarshal money, amount %s is not a string or an integer of subunits", v.Amount)
+		}
+		parsed, _ = BigMoneyFromSubunits(v.Currency, value, round)
+	}
+
+	*b = parsed
+	return nil
+}
+
+// String is an implementation of fmt.Stringer and returns the string
+// formatted representation of the monetary value.
+func (b BigMoney) String() string {
+	return b.StringStyle(b.currency.Negative)
+}
+
+// StringStyle returns the string formatted representation of the monetary
+// value, presenting negative values using the passed style.
+func (b BigMoney) StringStyle(s NegativeStyle) string {
+	return formatTemplate(b.currency, b.int().String(), s)
+}
+
+// StringNoSymbol returns the string formatted representation of the monetary
+// value without a currency symbol.
+func (b BigMoney) StringNoSymbol() string {
+	return formatSubunits(b.currency, b.int().String())
+}
+
+// int returns the value, treating an unset value as zero.
+func (b BigMoney) int() *big.Int {
+	if b.value == nil {
+		return new(big.Int)
+	}
+	return b.value
+}
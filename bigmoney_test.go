@@ -0,0 +1,144 @@
+package mongo
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func assertBigMoneyString(t *testing.T, b BigMoney, code string, formatted string) {
+	t.Helper()
+	if b.IsoCode() != code {
+		t.Errorf("Failed asserting code %s = %s (expected)\n", b.IsoCode(), code)
+	}
+
+	if b.String() != formatted {
+		t.Errorf("Failed asserting format %s = %s (expected)\n", b.String(), formatted)
+	}
+}
+
+func bigInt(t *testing.T, str string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		t.Fatalf("Failed to parse %s as a big integer", str)
+	}
+	return n
+}
+
+func TestBigMoneyFromSubunits(t *testing.T) {
+	b, err := BigMoneyFromSubunits("ZWD", bigInt(t, "-123456789012345678901234567890"), nil)
+	if err != nil {
+		t.Errorf("BigMoneyFromSubunits failed to recognise code 'ZWD'")
+	}
+	assertBigMoneyString(t, b, "ZWD", "Z$-1,234,567,890,123,456,789,012,345,678.90")
+
+	_, err = BigMoneyFromSubunits("XXX", big.NewInt(1), nil)
+	if err == nil {
+		t.Errorf("BigMoneyFromSubunits failed to error on code 'XXX'")
+	}
+
+	var zero BigMoney
+	assert(t, zero.IsZero())
+	assert(t, zero.Value().Sign() == 0)
+}
+
+func TestBigMoneyFromString(t *testing.T) {
+	b, err := BigMoneyFromString("VEF", "-Bs98,765,432,109,876,543,210.55", nil)
+	if err != nil {
+		t.Errorf("BigMoneyFromString failed: %s", err)
+	}
+	assert(t, b.Value().Cmp(bigInt(t, "-9876543210987654321055")) == 0)
+
+	_, err = BigMoneyFromString("VEF", "Bs1.5", nil)
+	if err == nil {
+		t.Errorf("BigMoneyFromString failed to error on too few subunits")
+	}
+}
+
+func TestBigMoneyConversion(t *testing.T) {
+	m, _ := MoneyFromSubunits("GBP", math.MaxInt64, RoundDown)
+	b := m.Big().Add(m.Big())
+	assertBigMoneyString(t, b, "GBP", "£184,467,440,737,095,516.14")
+
+	_, err := b.Money()
+	assert(t, errors.Is(err, ErrOverflow))
+
+	m2, err := b.Sub(m.Big()).Money()
+	assert(t, err == nil)
+	assert(t, m2.Eq(m))
+	assertValue(t, m2.Clone(7).Div(2).Value(), 3)
+}
+
+func TestBigMoneyArithmetic(t *testing.T) {
+	x, _ := BigMoneyFromSubunits("GBP", bigInt(t, "100000000000000000000"), nil)
+	y, _ := BigMoneyFromSubunits("GBP", big.NewInt(1), nil)
+
+	assert(t, x.Add(y).Value().Cmp(bigInt(t, "100000000000000000001")) == 0)
+	assert(t, x.Sub(y).Value().Cmp(bigInt(t, "99999999999999999999")) == 0)
+	assert(t, x.Mul(3).Value().Cmp(bigInt(t, "300000000000000000000")) == 0)
+	assert(t, x.FlipSign().Value().Cmp(bigInt(t, "-100000000000000000000")) == 0)
+	assert(t, x.FlipSign().Abs().Eq(x))
+	assert(t, x.Value().Cmp(bigInt(t, "100000000000000000000")) == 0)
+
+	assert(t, x.Gt(y) && x.Gte(y) && y.Lt(x) && y.Lte(x) && x.Neq(y) && !x.Eq(y))
+	assert(t, x.IsPos() && !x.IsNeg() && x.FlipSign().IsNeg())
+}
+
+func TestBigMoneyCurrencyPanic(t *testing.T) {
+	x, _ := BigMoneyFromSubunits("GBP", big.NewInt(1), nil)
+	y, _ := BigMoneyFromSubunits("EUR", big.NewInt(1), nil)
+
+	defer assertPanic(t)
+	x.Add(y)
+}
+
+func TestBigMoneySplit(t *testing.T) {
+	x, _ := BigMoneyFromSubunits("GBP", bigInt(t, "-100000000000000000000"), nil)
+	s := x.Split(3)
+	assert(t, s[0].Value().Cmp(bigInt(t, "-33333333333333333334")) == 0)
+	assert(t, s[1].Value().Cmp(bigInt(t, "-33333333333333333333")) == 0)
+	assert(t, s[2].Value().Cmp(bigInt(t, "-33333333333333333333")) == 0)
+	assert(t, s[0].Add(s[1]).Add(s[2]).Eq(x))
+}
+
+func TestBigMoneyAllocate(t *testing.T) {
+	x, _ := BigMoneyFromSubunits("GBP", bigInt(t, "1135354247000000000000"), nil)
+	s := x.Allocate(654, 465, 45565, 65, 4, 6542, 54, 574, 564, 6544, 9, 2342342, 237, 45, 34325, 2221, 111, 577, 7)
+	total := s[0]
+	for i := 1; i < len(s); i++ {
+		total = total.Add(s[i])
+	}
+	assert(t, total.Eq(x))
+}
+
+func TestBigMoneyAllocateByZero(t *testing.T) {
+	x, _ := BigMoneyFromSubunits("GBP", big.NewInt(100), nil)
+	defer assertPanic(t)
+	x.Allocate(0)
+}
+
+func TestBigMoneyJson(t *testing.T) {
+	x, _ := BigMoneyFromSubunits("GBP", bigInt(t, "100000000000000000000"), nil)
+	bytes, _ := json.Marshal(x)
+	assertJSON(t, bytes, `{"currency":"GBP","amount":"£1,000,000,000,000,000,000.00"}`)
+
+	var y BigMoney
+	err := json.Unmarshal(bytes, &y)
+	assert(t, err == nil)
+	assert(t, x.Eq(y))
+
+	err = json.Unmarshal([]byte(`{"currency":"GBP","amount":100000000000000000000}`), &y)
+	assert(t, err == nil)
+	assert(t, x.Eq(y))
+
+	z, _ := BigMoneyFromSubunits("GBP", bigInt(t, "0"), RoundDown)
+	err = json.Unmarshal(bytes, &z)
+	assert(t, err == nil)
+	assert(t, z.Rounding().String() == "HalfUp")
+
+	err = json.Unmarshal([]byte(`{"currency":"XXX","amount":1}`), &y)
+	assert(t, err != nil)
+}
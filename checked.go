@@ -0,0 +1,278 @@
+package mongo
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// The following methods are checked versions of the arithmetic and logical
+// operators. Instead of panicking they return an error wrapping one of
+// ErrCurrencyMismatch, ErrOverflow or ErrDivideByZero.
+
+// AddE is a checked arithmetic operator.
+func (m Money) AddE(v Money) (Money, error) {
+	if err := checkSameMoneyCurrency(m, v); err != nil {
+		return Money{}, err
+	}
+	value, overflow := addInt64(m.value, v.value)
+	if overflow {
+		return Money{}, fmt.Errorf("failed to add %s to %s, %w", v, m, ErrOverflow)
+	}
+	m.value = value
+	return m, nil
+}
+
+// SubE is a checked arithmetic operator.
+func (m Money) SubE(v Money) (Money, error) {
+	if err := checkSameMoneyCurrency(m, v); err != nil {
+		return Money{}, err
+	}
+	value, overflow := subInt64(m.value, v.value)
+	if overflow {
+		return Money{}, fmt.Errorf("failed to subtract %s from %s, %w", v, m, ErrOverflow)
+	}
+	m.value = value
+	return m, nil
+}
+
+// MulE is a checked arithmetic operator.
+func (m Money) MulE(n int64) (Money, error) {
+	value, overflow := mulInt64(m.value, n)
+	if overflow {
+		return Money{}, fmt.Errorf("failed to multiply %s by %d, %w", m, n, ErrOverflow)
+	}
+	m.value = value
+	return m, nil
+}
+
+// AbsE is a checked version of Abs.
+func (m Money) AbsE() (Money, error) {
+	if m.value < 0 {
+		return m.FlipSignE()
+	}
+	return m, nil
+}
+
+// FlipSignE is a checked version of FlipSign.
+func (m Money) FlipSignE() (Money, error) {
+	if m.value == math.MinInt64 {
+		return Money{}, fmt.Errorf("failed to flip the sign of %s, %w", m, ErrOverflow)
+	}
+	m.value = -m.value
+	return m, nil
+}
+
+// DivE is a checked arithmetic operator. This operation will perform rounding
+// of the resulting value using the assigned rounding function. The divisor is
+// treated as the exact decimal it represents.
+func (m Money) DivE(f float64) (Money, error) {
+	if f == 0 {
+		return Money{}, fmt.Errorf("failed to divide %s, %w", m, ErrDivideByZero)
+	}
+	r, err := floatToRat(f)
+	if err != nil {
+		return Money{}, fmt.Errorf("failed to divide %s, %w", m, err)
+	}
+	return m.mulRat(r.Inv(r))
+}
+
+// CmpE is a checked logical operator. It returns -1 if the money is less than
+// v, 0 if they are equal and 1 if it's greater than v.
+func (m Money) CmpE(v Money) (int, error) {
+	if err := checkSameMoneyCurrency(m, v); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.value < v.value:
+		return -1, nil
+	case m.value > v.value:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// SplitE is a checked version of Split.
+func (m Money) SplitE(n int64) ([]Money, error) {
+	return m.split(n)
+}
+
+// AllocateE is a checked version of Allocate. Each allocation is calculated
+// using a 128 bit intermediate value so only results that can't be
+// represented will overflow.
+func (m Money) AllocateE(ratios ...int64) ([]Money, error) {
+	return m.allocate(ratios...)
+}
+
+// AddE is a checked arithmetic operator.
+func (p Price) AddE(v Price) (Price, error) {
+	gross, err := p.gross.AddE(v.gross)
+	if err != nil {
+		return Price{}, err
+	}
+	t := p.taxes
+	for _, l := range v.taxes.detail {
+		if t, err = t.addE(l.Tax, l.Amount); err != nil {
+			return Price{}, err
+		}
+	}
+	d := p.discounts
+	for _, l := range v.discounts {
+		if d, err = d.addE(l.Discount, l.Amount); err != nil {
+			return Price{}, err
+		}
+	}
+	p.gross = gross
+	p.taxes = t
+	p.discounts = d
+	return p, nil
+}
+
+// MulE is a checked arithmetic operator.
+func (p Price) MulE(n int64) (Price, error) {
+	gross, err := p.gross.MulE(n)
+	if err != nil {
+		return Price{}, err
+	}
+	for _, l := range p.taxes.detail {
+		if _, err := l.Amount.MulE(n); err != nil {
+			return Price{}, err
+		}
+	}
+	if _, err := p.taxes.total.MulE(n); err != nil {
+		return Price{}, err
+	}
+	for _, l := range p.discounts {
+		if _, err := l.Amount.MulE(n); err != nil {
+			return Price{}, err
+		}
+	}
+	p = p.Mul(n)
+	p.gross = gross
+	return p, nil
+}
+
+// SubE is a checked arithmetic operator.
+func (p Price) SubE(v Price) (Price, error) {
+	gross, err := p.gross.SubE(v.gross)
+	if err != nil {
+		return Price{}, err
+	}
+	t := p.taxes
+	for _, l := range v.taxes.detail {
+		m, err := l.Amount.FlipSignE()
+		if err != nil {
+			return Price{}, err
+		}
+		if t, err = t.addE(l.Tax, m); err != nil {
+			return Price{}, err
+		}
+	}
+	d := p.discounts
+	for _, l := range v.discounts {
+		m, err := l.Amount.FlipSignE()
+		if err != nil {
+			return Price{}, err
+		}
+		if d, err = d.addE(l.Discount, m); err != nil {
+			return Price{}, err
+		}
+	}
+	p.gross = gross
+	p.taxes = t
+	p.discounts = d
+	return p, nil
+}
+
+// NegE is a checked version of Neg.
+func (p Price) NegE() (Price, error) {
+	if _, err := p.gross.FlipSignE(); err != nil {
+		return Price{}, err
+	}
+	if _, err := p.taxes.total.FlipSignE(); err != nil {
+		return Price{}, err
+	}
+	for _, l := range p.taxes.detail {
+		if _, err := l.Amount.FlipSignE(); err != nil {
+			return Price{}, err
+		}
+	}
+	for _, l := range p.discounts {
+		if _, err := l.Amount.FlipSignE(); err != nil {
+			return Price{}, err
+		}
+	}
+	return p.Neg(), nil
+}
+
+// DivE is a checked arithmetic operator. The gross and each tax are divided
+// exactly and rounded once using the gross price's rounding function.
+func (p Price) DivE(n int64) (Price, error) {
+	if n == 0 {
+		return Price{}, fmt.Errorf("failed to divide %s, %w", p, ErrDivideByZero)
+	}
+	r := big.NewRat(1, n)
+	result, err := p.parts(func(m Money) ([]Money, error) {
+		m.round = p.gross.round
+		v, err := m.mulRat(r)
+		return []Money{v}, err
+	})
+	if err != nil {
+		return Price{}, fmt.Errorf("failed to divide price, %w", err)
+	}
+	return result[0], nil
+}
+
+// SplitE is a checked version of Split.
+func (p Price) SplitE(n int64) ([]Price, error) {
+	return p.parts(func(m Money) ([]Money, error) {
+		return m.split(n)
+	})
+}
+
+// AllocateE is a checked version of Allocate.
+func (p Price) AllocateE(ratios ...int64) ([]Price, error) {
+	return p.parts(func(m Money) ([]Money, error) {
+		return m.allocate(ratios...)
+	})
+}
+
+// AddTaxE is a checked version of AddTax. The price is left unmodified if an
+// error is returned.
+func (p *Price) AddTaxE(m Money, desc string) error {
+	if err := checkSameMoneyCurrency(p.gross, m); err != nil {
+		return fmt.Errorf("failed to add tax '%s', %w", desc, err)
+	}
+	p.AddTax(m, desc)
+	return nil
+}
+
+// IncludeTaxE is a checked version of IncludeTax. The price is left
+// unmodified if an error is returned.
+func (p *Price) IncludeTaxE(m Money, desc string) error {
+	if err := checkSameMoneyCurrency(p.gross, m); err != nil {
+		return fmt.Errorf("failed to include tax '%s', %w", desc, err)
+	}
+	p.IncludeTax(m, desc)
+	return nil
+}
+
+// AddE is a checked arithmetic operator.
+func (b Bag) AddE(m ...Money) (Bag, error) {
+	return b.apply(m, Money.AddE)
+}
+
+// SubE is a checked arithmetic operator.
+func (b Bag) SubE(m ...Money) (Bag, error) {
+	return b.apply(m, Money.SubE)
+}
+
+// AddBagE is a checked arithmetic operator.
+func (b Bag) AddBagE(v Bag) (Bag, error) {
+	return b.AddE(v.Values()...)
+}
+
+// SubBagE is a checked arithmetic operator.
+func (b Bag) SubBagE(v Bag) (Bag, error) {
+	return b.SubE(v.Values()...)
+}
@@ -0,0 +1,225 @@
+package mongo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoneyAddE(t *testing.T) {
+	x, _ := MoneyGBP(67)
+	y, _ := MoneyGBP(33)
+	z, err := x.AddE(y)
+	assert(t, err == nil)
+	assertMoneyValue(t, z, 100)
+
+	y, _ = MoneyEUR(33)
+	_, err = x.AddE(y)
+	assert(t, errors.Is(err, ErrCurrencyMismatch))
+}
+
+func TestMoneySubE(t *testing.T) {
+	x, _ := MoneyGBP(67)
+	y, _ := MoneyGBP(33)
+	z, err := x.SubE(y)
+	assert(t, err == nil)
+	assertMoneyValue(t, z, 34)
+
+	y, _ = MoneyEUR(33)
+	_, err = x.SubE(y)
+	assert(t, errors.Is(err, ErrCurrencyMismatch))
+}
+
+func TestMoneyDivE(t *testing.T) {
+	x, _ := MoneyGBP(1337)
+	z, err := x.DivE(1.2457)
+	assert(t, err == nil)
+	assertMoneyValue(t, z, 1073)
+
+	_, err = x.DivE(0)
+	assert(t, errors.Is(err, ErrDivideByZero))
+
+	x, _ = MoneyGBP(math.MaxInt64)
+	_, err = x.DivE(0.5)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	_, err = x.DivE(math.NaN())
+	assert(t, err != nil)
+}
+
+func TestMoneyCmpE(t *testing.T) {
+	x, _ := MoneyGBP(67)
+	y, _ := MoneyGBP(33)
+
+	c, err := x.CmpE(y)
+	assert(t, err == nil && c == 1)
+
+	c, err = y.CmpE(x)
+	assert(t, err == nil && c == -1)
+
+	c, err = x.CmpE(x)
+	assert(t, err == nil && c == 0)
+
+	y, _ = MoneyUSD(33)
+	_, err = x.CmpE(y)
+	assert(t, errors.Is(err, ErrCurrencyMismatch))
+}
+
+func TestMoneySplitE(t *testing.T) {
+	x, _ := MoneyGBP(100)
+	s, err := x.SplitE(3)
+	assert(t, err == nil)
+	assertMoneyValue(t, s[0], 34)
+
+	_, err = x.SplitE(0)
+	assert(t, errors.Is(err, ErrDivideByZero))
+}
+
+func TestMoneyAllocateE(t *testing.T) {
+	x, _ := MoneyGBP(100)
+	s, err := x.AllocateE(1, 1, 1)
+	assert(t, err == nil)
+	assertMoneyValue(t, s[0], 34)
+
+	_, err = x.AllocateE()
+	assert(t, errors.Is(err, ErrDivideByZero))
+
+	_, err = x.AllocateE(0)
+	assert(t, errors.Is(err, ErrDivideByZero))
+}
+
+func TestPriceAddE(t *testing.T) {
+	p1, _ := PriceFromSubunits("GBP", 2083, nil)
+	p2, _ := PriceFromSubunits("GBP", 1545, nil)
+	p3, err := p1.AddE(p2)
+	assert(t, err == nil)
+	assertMoneyValue(t, p3.Gross(), 3628)
+
+	p2, _ = PriceFromSubunits("EUR", 1545, nil)
+	_, err = p1.AddE(p2)
+	assert(t, errors.Is(err, ErrCurrencyMismatch))
+}
+
+func TestPriceSubE(t *testing.T) {
+	p1, _ := PriceFromSubunits("GBP", 2083, nil)
+	p2, _ := PriceFromSubunits("GBP", 1545, nil)
+	p2.IncludeTaxPercent(20, "VAT")
+	p3, err := p1.SubE(p2)
+	assert(t, err == nil)
+	assertMoneyValue(t, p3.Gross(), 538)
+	assertMoneyValue(t, p3.Tax(), -257)
+
+	p2, _ = PriceFromSubunits("EUR", 1545, nil)
+	_, err = p1.SubE(p2)
+	assert(t, errors.Is(err, ErrCurrencyMismatch))
+}
+
+func TestPriceDivisionE(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 1000, nil)
+	p.IncludeTaxPercent(20, "VAT")
+
+	_, err := p.DivE(0)
+	assert(t, errors.Is(err, ErrDivideByZero))
+	_, err = p.SplitE(0)
+	assert(t, errors.Is(err, ErrDivideByZero))
+	_, err = p.AllocateE()
+	assert(t, errors.Is(err, ErrDivideByZero))
+	_, err = p.AllocateE(math.MaxInt64, 1)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	d, err := p.DivE(-4)
+	assert(t, err == nil)
+	assertMoneyValue(t, d.Gross(), -250)
+	assertMoneyValue(t, d.Tax(), -42)
+
+	n, _ := PriceFromSubunits("GBP", math.MinInt64, nil)
+	_, err = n.NegE()
+	assert(t, errors.Is(err, ErrOverflow))
+	_, err = n.DivE(-1)
+	assert(t, errors.Is(err, ErrOverflow))
+}
+
+func TestPriceTaxE(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 5500, nil)
+	m, _ := MoneyFromSubunits("GBP", 825, nil)
+	assert(t, p.AddTaxE(m, "VAT") == nil)
+	assertMoneyValue(t, p.Gross(), 6325)
+
+	assert(t, p.IncludeTaxE(m, "Levy") == nil)
+	assertMoneyValue(t, p.Net(), 4675)
+
+	m, _ = MoneyFromSubunits("EUR", 825, nil)
+	assert(t, errors.Is(p.AddTaxE(m, "VAT"), ErrCurrencyMismatch))
+	assert(t, errors.Is(p.IncludeTaxE(m, "VAT"), ErrCurrencyMismatch))
+	assertMoneyValue(t, p.Gross(), 6325)
+	assertMoneyValue(t, p.Tax(), 1650)
+}
+
+func TestMoneyOverflowE(t *testing.T) {
+	x, _ := MoneyGBP(math.MaxInt64)
+	y, _ := MoneyGBP(1)
+	z, _ := MoneyGBP(math.MinInt64)
+
+	_, err := x.AddE(y)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	_, err = z.SubE(y)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	_, err = x.MulE(2)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	_, err = z.MulE(-1)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	_, err = z.AbsE()
+	assert(t, errors.Is(err, ErrOverflow))
+
+	_, err = z.FlipSignE()
+	assert(t, errors.Is(err, ErrOverflow))
+
+	m, err := x.MulE(1)
+	assert(t, err == nil)
+	assertMoneyValue(t, m, math.MaxInt64)
+
+	m, err = z.Add(y).AbsE()
+	assert(t, err == nil)
+	assertMoneyValue(t, m, math.MaxInt64)
+}
+
+func TestMoneyAllocateOverflowE(t *testing.T) {
+	x, _ := MoneyGBP(math.MaxInt64)
+	s, err := x.AllocateE(1000000, 3000000)
+	assert(t, err == nil)
+	assertMoneyValue(t, s[0], 2305843009213693952)
+	assertMoneyValue(t, s[1], 6917529027641081855)
+	assertMoneyValue(t, s[0].Add(s[1]), math.MaxInt64)
+
+	_, err = x.AllocateE(math.MaxInt64, 1)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	_, err = x.AllocateE(2, -1)
+	assert(t, errors.Is(err, ErrOverflow))
+}
+
+func TestPriceOverflowE(t *testing.T) {
+	p1, _ := PriceFromSubunits("GBP", math.MaxInt64-100, nil)
+	p2, _ := PriceFromSubunits("GBP", 100, nil)
+	p2.IncludeTaxPercent(20, "VAT")
+
+	p3, err := p1.AddE(p2)
+	assert(t, err == nil)
+	assertMoneyValue(t, p3.Gross(), math.MaxInt64)
+	assertMoneyValue(t, p3.Tax(), 17)
+
+	_, err = p3.AddE(p2)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	_, err = p3.MulE(2)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	p4, err := p2.MulE(3)
+	assert(t, err == nil)
+	assertMoneyValue(t, p4.Gross(), 300)
+	assertMoneyValue(t, p4.Tax(), 51)
+}
@@ -0,0 +1,207 @@
+package mongo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+// maxSubunits is the largest number of subunits a currency can have while
+// still being able to represent one unit using an int64.
+const maxSubunits = 18
+
+// registry holds all recognised currencies and is safe for concurrent use.
+var registry = struct {
+	sync.RWMutex
+	currencies map[string]Currency
+	aliases    map[string]string
+}{
+	currencies: make(map[string]Currency, len(builtinCurrencies)),
+	aliases:    make(map[string]string, len(builtinAliases)),
+}
+
+func init() {
+	for code, c := range builtinCurrencies {
+		registry.currencies[code] = c
+	}
+	for alias, code := range builtinAliases {
+		registry.aliases[strings.ToUpper(alias)] = code
+	}
+}
+
+// RegisterCurrency adds a currency to the registry so it can be used to create
+// money. If a currency with the same code is already registered it's
+// replaced. Money created before the replacement keeps the definition it was
+// created with.
+func RegisterCurrency(c Currency) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	registry.currencies[c.Code] = c
+	return nil
+}
+
+// UnregisterCurrency removes a currency from the registry, returning false if
+// it wasn't registered. Money already created using the currency is unaffected.
+func UnregisterCurrency(code string) bool {
+	registry.Lock()
+	defer registry.Unlock()
+	_, ok := registry.currencies[code]
+	delete(registry.currencies, code)
+	return ok
+}
+
+// LookupCurrency returns the registered currency with the passed code.
+func LookupCurrency(code string) (Currency, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	c, ok := registry.currencies[code]
+	return c, ok
+}
+
+// LookupCurrencyByNumeric returns the registered currency with the passed ISO
+// 4217 numeric code. If more than one currency uses the code, a currency that
+// hasn't been withdrawn is preferred.
+func LookupCurrencyByNumeric(numeric int) (Currency, bool) {
+	if numeric <= 0 {
+		return Currency{}, false
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	var result Currency
+	var found bool
+	for _, c := range registry.currencies {
+		if c.Numeric != numeric {
+			continue
+		}
+		if !found || (result.Withdrawn && !c.Withdrawn) || (result.Withdrawn == c.Withdrawn && c.Code < result.Code) {
+			result, found = c, true
+		}
+	}
+	return result, found
+}
+
+// RegisterCurrencyAlias adds an alternative name or symbol for a registered
+// currency, e.g. "RMB" for CNY. Aliases are used to detect the currency of a
+// formatted string and are case insensitive. If the alias is already
+// registered it's replaced.
+func RegisterCurrencyAlias(alias string, code string) error {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return fmt.Errorf("the currency alias for '%s' is empty", code)
+	}
+	if strings.ContainsAny(alias, "0123456789") {
+		return fmt.Errorf("the currency alias '%s' must not contain digits", alias)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.currencies[code]; !ok {
+		return fmt.Errorf("the currency code '%s' is not recognised", code)
+	}
+	registry.aliases[strings.ToUpper(alias)] = code
+	return nil
+}
+
+// UnregisterCurrencyAlias removes a currency alias, returning false if it
+// wasn't registered.
+func UnregisterCurrencyAlias(alias string) bool {
+	alias = strings.ToUpper(strings.TrimSpace(alias))
+	registry.Lock()
+	defer registry.Unlock()
+	_, ok := registry.aliases[alias]
+	delete(registry.aliases, alias)
+	return ok
+}
+
+// LookupCurrencyAlias returns the registered currency the alias refers to.
+func LookupCurrencyAlias(alias string) (Currency, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	code, ok := registry.aliases[strings.ToUpper(strings.TrimSpace(alias))]
+	if !ok {
+		return Currency{}, false
+	}
+	c, ok := registry.currencies[code]
+	return c, ok
+}
+
+// Currencies returns all registered currencies sorted by code.
+func Currencies() []Currency {
+	registry.RLock()
+	s := make([]Currency, 0, len(registry.currencies))
+	for _, c := range registry.currencies {
+		s = append(s, c)
+	}
+	registry.RUnlock()
+
+	slices.SortFunc(s, func(a, b Currency) bool {
+		return a.Code < b.Code
+	})
+	return s
+}
+
+// lookupCurrency returns the registered currency with the passed code or an
+// error if it's not recognised.
+func lookupCurrency(code string) (Currency, error) {
+	c, ok := LookupCurrency(code)
+	if !ok {
+		return Currency{}, fmt.Errorf("the currency code '%s' is not recognised", code)
+	}
+	return c, nil
+}
+
+// Validate checks the currency definition can be used to create and format
+// money.
+func (c Currency) validate() error {
+	if c.Code == "" || strings.IndexFunc(c.Code, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("the currency code '%s' is not valid", c.Code)
+	}
+	if c.Numeric < 0 || c.Numeric > 999 {
+		return fmt.Errorf("the currency '%s' numeric code %d is not valid", c.Code, c.Numeric)
+	}
+	if c.Exponent < -1 {
+		return fmt.Errorf("the currency '%s' exponent %d is not valid", c.Code, c.Exponent)
+	}
+	if c.Subunits < 0 || c.Subunits > maxSubunits {
+		return fmt.Errorf("the currency '%s' has %d subunits, it must have between 0 and %d", c.Code, c.Subunits, maxSubunits)
+	}
+	if c.Subunits > 0 && c.SubSep == "" {
+		return fmt.Errorf("the currency '%s' has subunits but no subunit separator", c.Code)
+	}
+	if c.ThouSep != "" && c.ThouSep == c.SubSep {
+		return fmt.Errorf("the currency '%s' uses the same thousand and subunit separator", c.Code)
+	}
+	if strings.Count(c.Template, "0") != 1 || strings.ContainsAny(c.Template, "123456789") {
+		return fmt.Errorf("the currency '%s' template '%s' must contain a single '0' and no other digits", c.Code, c.Template)
+	}
+	if !c.Negative.valid() {
+		return fmt.Errorf("the currency '%s' has an invalid negative style %d", c.Code, c.Negative)
+	}
+	return nil
+}
+
+// NumericCode returns the ISO 4217 numeric code as a three digit string, e.g.
+// "008" for ALL, or an empty string if the currency doesn't have one.
+func (c Currency) NumericCode() string {
+	if c.Numeric <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%03d", c.Numeric)
+}
+
+// pow10 returns 10 to the power of n.
+func pow10(n int) int64 {
+	var p int64 = 1
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
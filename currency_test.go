@@ -0,0 +1,190 @@
+package mongo
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBuiltinCurrenciesAreValid(t *testing.T) {
+	for code, c := range builtinCurrencies {
+		if code != c.Code {
+			t.Errorf("Currency %s is registered under the code %s", c.Code, code)
+		}
+		if err := c.validate(); err != nil {
+			t.Errorf("Currency %s is invalid: %s", code, err)
+		}
+	}
+}
+
+func TestRegisterCurrency(t *testing.T) {
+	defer UnregisterCurrency("XBT")
+
+	_, err := MoneyFromSubunits("XBT", 1, nil)
+	if err == nil {
+		t.Errorf("MoneyFromSubunits failed to error on unregistered code 'XBT'")
+	}
+
+	err = RegisterCurrency(Currency{Code: "XBT", Subunits: 8, ThouSep: ",", SubSep: ".", Template: "₿0"})
+	if err != nil {
+		t.Errorf("RegisterCurrency failed: %s", err)
+	}
+
+	m, _ := MoneyFromSubunits("XBT", 123456789012, nil)
+	assertMoneyString(t, m, "XBT", "₿1,234.56789012")
+	assertMoneyUnits(t, m, 1234, 56789012)
+	assert(t, m.Currency().Subunits == 8)
+
+	m, _ = MoneyFromString("XBT", "₿0.00000001", nil)
+	assertMoneyValue(t, m, 1)
+
+	c, ok := LookupCurrency("XBT")
+	assert(t, ok && c.Template == "₿0")
+}
+
+func TestReplaceCurrency(t *testing.T) {
+	original, _ := LookupCurrency("GBP")
+	defer RegisterCurrency(original)
+
+	before, _ := MoneyGBP(123456)
+
+	err := RegisterCurrency(Currency{Code: "GBP", Subunits: 2, ThouSep: " ", SubSep: ",", Template: "0 £"})
+	if err != nil {
+		t.Errorf("RegisterCurrency failed: %s", err)
+	}
+
+	after, _ := MoneyGBP(123456)
+	assertMoneyString(t, after, "GBP", "1 234,56 £")
+	assertMoneyString(t, before, "GBP", "£1,234.56")
+	assertMoneyValue(t, before.Add(after), 246912)
+}
+
+func TestUnregisterCurrency(t *testing.T) {
+	original, _ := LookupCurrency("EEK")
+	defer RegisterCurrency(original)
+
+	m, _ := MoneyFromSubunits("EEK", 1055, nil)
+
+	assert(t, UnregisterCurrency("EEK"))
+	assert(t, !UnregisterCurrency("EEK"))
+
+	_, ok := LookupCurrency("EEK")
+	assert(t, !ok)
+
+	_, err := MoneyFromSubunits("EEK", 1055, nil)
+	assert(t, err != nil)
+
+	assertMoneyString(t, m, "EEK", "kr10.55")
+}
+
+func TestCurrencies(t *testing.T) {
+	s := Currencies()
+	assert(t, len(s) == len(builtinCurrencies))
+	for i := 1; i < len(s); i++ {
+		assert(t, s[i-1].Code < s[i].Code)
+	}
+}
+
+func TestRegisterCurrencyErrors(t *testing.T) {
+	for _, c := range []Currency{
+		{Code: "", Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0"},
+		{Code: "X Y", Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0"},
+		{Code: "XXX", Subunits: -1, ThouSep: ",", SubSep: ".", Template: "0"},
+		{Code: "XXX", Subunits: 19, ThouSep: ",", SubSep: ".", Template: "0"},
+		{Code: "XXX", Subunits: 2, ThouSep: ",", SubSep: "", Template: "0"},
+		{Code: "XXX", Subunits: 2, ThouSep: ".", SubSep: ".", Template: "0"},
+		{Code: "XXX", Subunits: 2, ThouSep: ",", SubSep: ".", Template: "X"},
+		{Code: "XXX", Subunits: 2, ThouSep: ",", SubSep: ".", Template: "X 20"},
+		{Code: "XXX", Numeric: 1000, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0"},
+		{Code: "XXX", Exponent: -2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0"},
+	} {
+		if err := RegisterCurrency(c); err == nil {
+			t.Errorf("RegisterCurrency failed to error on %#v", c)
+		}
+	}
+	_, ok := LookupCurrency("XXX")
+	assert(t, !ok)
+}
+
+func TestCurrencyRegistryConcurrency(t *testing.T) {
+	defer UnregisterCurrency("PTS")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterCurrency(Currency{Code: "PTS", Subunits: 0, ThouSep: ",", Template: "0 pts"})
+			UnregisterCurrency("PTS")
+		}()
+		go func() {
+			defer wg.Done()
+			MoneyGBP(1)
+			Currencies()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCurrencyMetadata(t *testing.T) {
+	c, _ := LookupCurrency("GBP")
+	assert(t, c.Numeric == 826)
+	assert(t, c.NumericCode() == "826")
+	assert(t, c.Name == "Pound Sterling")
+	assert(t, c.Exponent == 2)
+	assert(t, !c.Withdrawn)
+
+	c, _ = LookupCurrency("ALL")
+	assert(t, c.NumericCode() == "008")
+
+	c, _ = LookupCurrency("HUF")
+	assert(t, c.Exponent == 2 && c.Subunits == 0)
+
+	c, _ = LookupCurrency("XAU")
+	assert(t, c.Exponent == -1)
+
+	c, _ = LookupCurrency("JEP")
+	assert(t, c.Numeric == 0 && c.NumericCode() == "")
+
+	for _, code := range []string{"EEK", "BYR", "GHC", "VEF", "ZWD"} {
+		c, _ = LookupCurrency(code)
+		assert(t, c.Withdrawn)
+	}
+}
+
+func TestBuiltinNumericCodesAreUnique(t *testing.T) {
+	seen := make(map[int]string)
+	for code, c := range builtinCurrencies {
+		if c.Numeric == 0 {
+			continue
+		}
+		if other, ok := seen[c.Numeric]; ok {
+			t.Errorf("Currencies %s and %s share the numeric code %d", code, other, c.Numeric)
+		}
+		seen[c.Numeric] = code
+	}
+}
+
+func TestLookupCurrencyByNumeric(t *testing.T) {
+	c, ok := LookupCurrencyByNumeric(826)
+	assert(t, ok && c.Code == "GBP")
+
+	c, ok = LookupCurrencyByNumeric(978)
+	assert(t, ok && c.Code == "EUR")
+
+	c, ok = LookupCurrencyByNumeric(233)
+	assert(t, ok && c.Code == "EEK")
+
+	_, ok = LookupCurrencyByNumeric(0)
+	assert(t, !ok)
+
+	_, ok = LookupCurrencyByNumeric(999)
+	assert(t, !ok)
+
+	defer UnregisterCurrency("XGB")
+	defer UnregisterCurrency("XGA")
+	RegisterCurrency(Currency{Code: "XGA", Numeric: 826, Subunits: 2, SubSep: ".", Template: "0", Withdrawn: true})
+	RegisterCurrency(Currency{Code: "XGB", Numeric: 826, Subunits: 2, SubSep: ".", Template: "0"})
+
+	c, ok = LookupCurrencyByNumeric(826)
+	assert(t, ok && c.Code == "GBP")
+}
@@ -0,0 +1,132 @@
+package mongo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+// AmbiguousCurrencyError is returned when a currency symbol matches more than
+// one currency and no preference resolves it.
+type AmbiguousCurrencyError struct {
+	Symbol     string   // The currency symbol found.
+	Candidates []string // The codes of the matching currencies, sorted.
+}
+
+// Error is an implementation of error.
+func (e *AmbiguousCurrencyError) Error() string {
+	return fmt.Sprintf("the currency symbol '%s' is ambiguous, it matches %s", e.Symbol, strings.Join(e.Candidates, ", "))
+}
+
+// Unwrap returns ErrAmbiguousCurrency.
+func (e *AmbiguousCurrencyError) Unwrap() error {
+	return ErrAmbiguousCurrency
+}
+
+// dashRegex matches a dash used in place of zero subunits, e.g. "12.–".
+var dashRegex = regexp.MustCompile(`([0-9])[.,][–—-]`)
+
+// ParseMoney constructs a new money object from a string, detecting the
+// currency from an ISO 4217 code, a currency symbol or an alias, e.g. "£10.55",
+// "10.55 EUR", "US$ 5" or "CHF 12.–". If a symbol matches more than one
+// currency, the first matching code in prefer is used, otherwise an
+// AmbiguousCurrencyError is returned.
+func ParseMoney(str string, prefer ...string) (Money, error) {
+	curr, err := DetectCurrency(str, prefer...)
+	if err != nil {
+		return Money{}, err
+	}
+	str = dashRegex.ReplaceAllString(str, "$1")
+	return Parser{Mode: ParseLenient}.Parse(curr.Code, str, nil)
+}
+
+// DetectCurrency returns the currency of a formatted monetary value using the
+// text around the number. The text can be an ISO 4217 code, a registered alias
+// or a currency symbol. If a symbol matches more than one currency, the first
+// matching code in prefer is used, otherwise an AmbiguousCurrencyError is
+// returned.
+func DetectCurrency(str string, prefer ...string) (Currency, error) {
+	str = dashRegex.ReplaceAllString(str, "$1")
+
+	first := strings.IndexFunc(str, isASCIIDigit)
+	if first < 0 {
+		return Currency{}, fmt.Errorf("failed to detect currency, no number found in '%s'", str)
+	}
+	last := strings.LastIndexFunc(str, isASCIIDigit)
+
+	before := strings.TrimFunc(str[:first], isSignOrSpace)
+	after := strings.TrimFunc(str[last+1:], isSignOrSpace)
+	if fields := strings.Fields(after); len(fields) > 0 {
+		if end := fields[len(fields)-1]; strings.EqualFold(end, "CR") || strings.EqualFold(end, "DR") {
+			after = strings.TrimFunc(strings.TrimSuffix(after, end), isSignOrSpace)
+		}
+	}
+
+	if before != "" && after != "" {
+		return Currency{}, fmt.Errorf("failed to detect currency, found both '%s' and '%s' in '%s'", before, after, str)
+	}
+	token := before + after
+	if token == "" {
+		return Currency{}, fmt.Errorf("failed to detect currency, no code or symbol found in '%s'", str)
+	}
+
+	if c, ok := LookupCurrency(strings.ToUpper(token)); ok {
+		return c, nil
+	}
+
+	candidates := symbolCandidates(token)
+	alias, isAlias := LookupCurrencyAlias(token)
+	if isAlias && !slices.Contains(candidates, alias.Code) {
+		candidates = append(candidates, alias.Code)
+		slices.Sort(candidates)
+	}
+
+	for _, code := range prefer {
+		if slices.Contains(candidates, code) {
+			c, _ := LookupCurrency(code)
+			return c, nil
+		}
+	}
+	if isAlias {
+		return alias, nil
+	}
+
+	switch len(candidates) {
+	case 0:
+		return Currency{}, fmt.Errorf("failed to detect currency, '%s' is not a recognised code or symbol", token)
+	case 1:
+		c, _ := LookupCurrency(candidates[0])
+		return c, nil
+	}
+	return Currency{}, &AmbiguousCurrencyError{Symbol: token, Candidates: candidates}
+}
+
+// symbolCandidates returns the sorted codes of all registered currencies using
+// the passed symbol, ignoring case. Withdrawn currencies are only included if no
+// current currency uses the symbol.
+func symbolCandidates(symbol string) []string {
+	var current, withdrawn []string
+	for _, c := range Currencies() {
+		if !strings.EqualFold(c.Symbol(), symbol) {
+			continue
+		}
+		if c.Withdrawn {
+			withdrawn = append(withdrawn, c.Code)
+		} else {
+			current = append(current, c.Code)
+		}
+	}
+	if len(current) > 0 {
+		return current
+	}
+	return withdrawn
+}
+
+// isSignOrSpace returns true if the rune is white space or used to mark the
+// sign of a monetary value.
+func isSignOrSpace(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("-+()", r)
+}
@@ -0,0 +1,107 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		str      string
+		code     string
+		expected int64
+	}{
+		{"£10.55", "GBP", 1055},
+		{"-£10.55", "GBP", -1055},
+		{"10.55 EUR", "EUR", 1055},
+		{"10.55 eur", "EUR", 1055},
+		{"€1,234.56", "EUR", 123456},
+		{"US$ 5", "USD", 500},
+		{"RMB 100", "CNY", 10000},
+		{"NIS 20.50", "ILS", 2050},
+		{"CHF 12.–", "CHF", 1200},
+		{"CHF 12.-", "CHF", 1200},
+		{"(CHF 12.50)", "CHF", -1250},
+		{"₹1,234.00", "INR", 123400},
+		{"₺5", "TRY", 500},
+		{"10.55 CHF CR", "CHF", -1055},
+		{"JPY 1,234", "JPY", 1234},
+	}
+	for _, test := range tests {
+		m, err := ParseMoney(test.str)
+		if err != nil {
+			t.Errorf("ParseMoney(%s) failed: %s", test.str, err)
+			continue
+		}
+		assertMoneyString(t, m, test.code, m.String())
+		assertMoneyValue(t, m, test.expected)
+	}
+}
+
+func TestParseMoneyAmbiguous(t *testing.T) {
+	_, err := ParseMoney("$10.55")
+	if !errors.Is(err, ErrAmbiguousCurrency) {
+		t.Errorf("ParseMoney failed to return ErrAmbiguousCurrency, returned %v", err)
+	}
+
+	var aerr *AmbiguousCurrencyError
+	if errors.As(err, &aerr) {
+		assert(t, aerr.Symbol == "$")
+		for _, code := range []string{"AUD", "CAD", "USD"} {
+			found := false
+			for _, c := range aerr.Candidates {
+				found = found || c == code
+			}
+			if !found {
+				t.Errorf("AmbiguousCurrencyError candidates %v don't contain %s", aerr.Candidates, code)
+			}
+		}
+	}
+
+	m, err := ParseMoney("$10.55", "EUR", "CAD", "USD")
+	assert(t, err == nil)
+	assertMoneyString(t, m, "CAD", "$10.55")
+
+	m, err = ParseMoney("£10.55", "GIP")
+	assert(t, err == nil)
+	assertMoneyString(t, m, "GIP", "£10.55")
+
+	_, err = ParseMoney("kr 10,55")
+	assert(t, errors.Is(err, ErrAmbiguousCurrency))
+
+	m, err = ParseMoney("kr 10,55", "DKK")
+	assert(t, err == nil)
+	assertMoneyString(t, m, "DKK", "kr 10,55")
+}
+
+func TestParseMoneyErrors(t *testing.T) {
+	for _, str := range []string{"", "£", "10.55", "XXX 10", "£10.55 EUR", "10-11 GBP"} {
+		if _, err := ParseMoney(str); err == nil {
+			t.Errorf("ParseMoney(%s) failed to error", str)
+		}
+	}
+}
+
+func TestCurrencyAlias(t *testing.T) {
+	defer UnregisterCurrencyAlias("Quid")
+
+	_, err := ParseMoney("10 quid")
+	assert(t, err != nil)
+
+	if err := RegisterCurrencyAlias("Quid", "GBP"); err != nil {
+		t.Errorf("RegisterCurrencyAlias failed: %s", err)
+	}
+	m, err := ParseMoney("10 quid")
+	assert(t, err == nil)
+	assertMoneyString(t, m, "GBP", "£10.00")
+
+	c, ok := LookupCurrencyAlias("QUID")
+	assert(t, ok && c.Code == "GBP")
+
+	assert(t, UnregisterCurrencyAlias("quid"))
+	assert(t, !UnregisterCurrencyAlias("quid"))
+
+	assert(t, RegisterCurrencyAlias("", "GBP") != nil)
+	assert(t, RegisterCurrencyAlias("X1", "GBP") != nil)
+	assert(t, RegisterCurrencyAlias("Quid", "XXX") != nil)
+}
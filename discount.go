@@ -0,0 +1,565 @@
+package mongo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// DiscountKind specifies how the amount of a discount is calculated.
+type DiscountKind int
+
+const (
+	// DiscountPercent discounts are a percentage of the price, e.g. 10% off.
+	DiscountPercent DiscountKind = iota
+
+	// DiscountAmount discounts are a fixed amount off the price, e.g. £5 off.
+	// The price is never discounted below zero.
+	DiscountAmount
+
+	// DiscountOverride discounts replace the price with a fixed amount. A
+	// price that's already lower isn't changed.
+	DiscountOverride
+)
+
+// discountKindNames are the names of the discount kinds used for text
+// encoding.
+var discountKindNames = []string{"percent", "amount", "override"}
+
+// String is an implementation of fmt.Stringer.
+func (k DiscountKind) String() string {
+	switch k {
+	case DiscountPercent:
+		return "DiscountPercent"
+	case DiscountAmount:
+		return "DiscountAmount"
+	case DiscountOverride:
+		return "DiscountOverride"
+	}
+	return fmt.Sprintf("DiscountKind(%d)", int(k))
+}
+
+// MarshalText is an implementation of encoding.TextMarshaler, e.g.
+// "percent".
+func (k DiscountKind) MarshalText() ([]byte, error) {
+	if k < 0 || int(k) >= len(discountKindNames) {
+		return nil, fmt.Errorf("failed to marshal discount kind %d", int(k))
+	}
+	return []byte(discountKindNames[k]), nil
+}
+
+// UnmarshalText is an implementation of encoding.TextUnmarshaler.
+func (k *DiscountKind) UnmarshalText(text []byte) error {
+	i := slices.Index(discountKindNames, string(text))
+	if i < 0 {
+		return fmt.Errorf("failed to unmarshal discount kind '%s'", text)
+	}
+	*k = DiscountKind(i)
+	return nil
+}
+
+// DiscountTiming specifies whether a discount is calculated on the price
+// before or after tax.
+type DiscountTiming int
+
+const (
+	// DiscountBeforeTax discounts are calculated on the net and reduce it.
+	// The taxes are then recalculated on the discounted net.
+	DiscountBeforeTax DiscountTiming = iota
+
+	// DiscountAfterTax discounts are calculated on the gross and reduce it.
+	// The taxes are then recalculated as if they were included in the
+	// discounted gross.
+	DiscountAfterTax
+)
+
+// String is an implementation of fmt.Stringer.
+func (t DiscountTiming) String() string {
+	switch t {
+	case DiscountBeforeTax:
+		return "DiscountBeforeTax"
+	case DiscountAfterTax:
+		return "DiscountAfterTax"
+	}
+	return fmt.Sprintf("DiscountTiming(%d)", int(t))
+}
+
+// MarshalText is an implementation of encoding.TextMarshaler, e.g. "before".
+func (t DiscountTiming) MarshalText() ([]byte, error) {
+	switch t {
+	case DiscountBeforeTax:
+		return []byte("before"), nil
+	case DiscountAfterTax:
+		return []byte("after"), nil
+	}
+	return nil, fmt.Errorf("failed to marshal discount timing %d", int(t))
+}
+
+// UnmarshalText is an implementation of encoding.TextUnmarshaler.
+func (t *DiscountTiming) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "before":
+		*t = DiscountBeforeTax
+	case "after":
+		*t = DiscountAfterTax
+	default:
+		return fmt.Errorf("failed to unmarshal discount timing '%s'", text)
+	}
+	return nil
+}
+
+// Discount defines how a price is reduced. Discounts applied to a price are
+// kept separate unless they have the same ID or, if they have no ID, the same
+// label.
+type Discount struct {
+	ID          string         // The unique identifier of the discount, optional.
+	Description string         // The description of the discount, e.g. "Spring sale".
+	Kind        DiscountKind   // How the amount of the discount is calculated.
+	Timing      DiscountTiming // Whether the discount is calculated before or after tax.
+	Rate        *big.Rat       // The percentage, e.g. 10 for 10% off. Only used by percentage discounts.
+	Amount      Money          // The amount off or the replacement price. Unused by percentage discounts.
+	Minimum     Money          // The minimum price the discount applies to, optional.
+}
+
+// PercentDiscount constructs a discount that's a percentage of the price
+// before tax. The percentage is a decimal string, e.g. "12.5".
+func PercentDiscount(desc string, percent string) (Discount, error) {
+	r, err := parseDecimal(percent)
+	if err != nil {
+		return Discount{}, fmt.Errorf("failed to create discount '%s', %w", desc, err)
+	}
+	d := Discount{Description: desc, Kind: DiscountPercent, Rate: r}
+	if err := d.validate(); err != nil {
+		return Discount{}, err
+	}
+	return d, nil
+}
+
+// AmountDiscount constructs a discount that's a fixed amount off the price
+// before tax.
+func AmountDiscount(desc string, amount Money) Discount {
+	return Discount{Description: desc, Kind: DiscountAmount, Amount: amount}
+}
+
+// OverrideDiscount constructs a discount that replaces the price before tax.
+func OverrideDiscount(desc string, price Money) Discount {
+	return Discount{Description: desc, Kind: DiscountOverride, Amount: price}
+}
+
+// Label returns the description of the discount with its rate or amount, e.g.
+// "Spring sale 10%" or "Voucher £5.00".
+func (d Discount) Label() string {
+	var sb strings.Builder
+	sb.WriteString(d.Description)
+	if d.Kind == DiscountPercent && d.Rate != nil {
+		fmt.Fprintf(&sb, " %s%%", ratString(d.Rate))
+	} else if d.Kind != DiscountPercent && d.Amount.currency.Code != "" {
+		fmt.Fprintf(&sb, " %s", d.Amount)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// String is an implementation of fmt.Stringer, e.g.
+// "Spring sale 10% DiscountPercent DiscountBeforeTax".
+func (d Discount) String() string {
+	str := fmt.Sprintf("%s %s %s", d.Label(), d.Kind, d.Timing)
+	if d.Minimum.currency.Code != "" {
+		str += fmt.Sprintf(" over %s", d.Minimum)
+	}
+	return str
+}
+
+// MarshalJSON is an implementation of json.Marshaller.
+func (d Discount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.toJSON())
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. Fixed and minimum
+// amounts must be full money objects, e.g. {"currency":"GBP","amount":"£5.00"}.
+func (d *Discount) UnmarshalJSON(b []byte) error {
+	var v discountJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal discount, %w", err)
+	}
+	discount, err := v.discount(Money{})
+	if err != nil {
+		return err
+	}
+	*d = discount
+	return nil
+}
+
+// validate checks the discount can be calculated.
+func (d Discount) validate() error {
+	switch d.Kind {
+	case DiscountPercent:
+		if d.Rate == nil {
+			return fmt.Errorf("invalid discount '%s', no rate specified", d.Description)
+		}
+		if d.Rate.Sign() < 0 || d.Rate.Cmp(big.NewRat(100, 1)) > 0 {
+			return fmt.Errorf("invalid discount '%s', rate %s%% must be between 0%% and 100%%", d.Description, ratString(d.Rate))
+		}
+	case DiscountAmount, DiscountOverride:
+		if d.Amount.currency.Code == "" {
+			return fmt.Errorf("invalid discount '%s', no amount specified", d.Description)
+		}
+		if d.Amount.IsNeg() {
+			return fmt.Errorf("invalid discount '%s', amount %s must not be negative", d.Description, d.Amount)
+		}
+	default:
+		return fmt.Errorf("invalid discount '%s', unknown kind %d", d.Description, d.Kind)
+	}
+	if d.Timing != DiscountBeforeTax && d.Timing != DiscountAfterTax {
+		return fmt.Errorf("invalid discount '%s', unknown timing %d", d.Description, d.Timing)
+	}
+	return nil
+}
+
+// check validates the discount before it's applied to a price with the
+// passed currency.
+func (d Discount) check(m Money) error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+	if d.Kind != DiscountPercent {
+		if err := checkSameMoneyCurrency(m, d.Amount); err != nil {
+			return fmt.Errorf("invalid discount '%s', %w", d.Description, err)
+		}
+	}
+	if d.Minimum.currency.Code != "" {
+		if err := checkSameMoneyCurrency(m, d.Minimum); err != nil {
+			return fmt.Errorf("invalid discount '%s', %w", d.Description, err)
+		}
+	}
+	return nil
+}
+
+// calculate returns the amount the discount takes off the passed price, which
+// is the net or gross depending on the discount's timing. The amount is zero
+// if the price is below the discount's minimum.
+func (d Discount) calculate(m Money) (Money, error) {
+	if m.IsNeg() {
+		return Money{}, fmt.Errorf("failed to calculate discount '%s', price %s is negative", d.Description, m)
+	}
+	if d.Minimum.currency.Code != "" && m.value < d.Minimum.value {
+		return m.Clone(0), nil
+	}
+	switch d.Kind {
+	case DiscountPercent:
+		r := new(big.Rat).SetInt64(m.value)
+		r.Mul(r, d.Rate).Quo(r, big.NewRat(100, 1))
+		value := roundRat(r, m.round)
+		if !value.IsInt64() {
+			return Money{}, fmt.Errorf("failed to calculate discount '%s', %w", d.Description, ErrOverflow)
+		}
+		return m.Clone(value.Int64()), nil
+	case DiscountAmount:
+		if d.Amount.value > m.value {
+			return m, nil
+		}
+		return m.Clone(d.Amount.value), nil
+	case DiscountOverride:
+		if d.Amount.value > m.value {
+			return m.Clone(0), nil
+		}
+		return m.Clone(m.value - d.Amount.value), nil
+	}
+	return Money{}, fmt.Errorf("invalid discount '%s', unknown kind %d", d.Description, d.Kind)
+}
+
+// key returns the value used to decide if two discounts are the same.
+func (d Discount) key() string {
+	if d.ID != "" {
+		return d.ID
+	}
+	return d.Label()
+}
+
+// clone returns a copy of the discount that doesn't share its rate.
+func (d Discount) clone() Discount {
+	if d.Rate != nil {
+		d.Rate = new(big.Rat).Set(d.Rate)
+	}
+	return d
+}
+
+// toJSON returns the JSON representation of the discount.
+func (d Discount) toJSON() discountJSON {
+	v := discountJSON{
+		ID:          d.ID,
+		Description: d.Description,
+		Label:       d.Label(),
+		Kind:        d.Kind,
+		Timing:      d.Timing,
+	}
+	if d.Rate != nil {
+		v.Rate = rateString(d.Rate)
+	}
+	if d.Amount.currency.Code != "" {
+		v.Fixed, _ = d.Amount.MarshalJSON()
+	}
+	if d.Minimum.currency.Code != "" {
+		v.Minimum, _ = d.Minimum.MarshalJSON()
+	}
+	return v
+}
+
+// discountJSON is the JSON representation of a discount. The amount is only
+// used by discount lines.
+type discountJSON struct {
+	ID          string          `json:"id,omitempty"`
+	Description string          `json:"description"`
+	Label       string          `json:"label,omitempty"`
+	Kind        DiscountKind    `json:"kind"`
+	Timing      DiscountTiming  `json:"timing"`
+	Rate        string          `json:"rate,omitempty"`
+	Fixed       json.RawMessage `json:"fixed,omitempty"`
+	Minimum     json.RawMessage `json:"minimum,omitempty"`
+	Amount      json.RawMessage `json:"amount,omitempty"`
+}
+
+// discount returns the discount the JSON represents. Bare fixed and minimum
+// amount strings are parsed using the currency of the passed money object.
+func (v discountJSON) discount(currency Money) (Discount, error) {
+	d := Discount{
+		ID:          v.ID,
+		Description: v.Description,
+		Kind:        v.Kind,
+		Timing:      v.Timing,
+	}
+	if v.Rate != "" {
+		r, err := parseRate(v.Rate)
+		if err != nil {
+			return Discount{}, fmt.Errorf("failed to unmarshal discount '%s', %w", v.Description, err)
+		}
+		d.Rate = r
+	}
+	if len(v.Fixed) > 0 {
+		d.Amount = currency.Clone(0)
+		if err := json.Unmarshal(v.Fixed, &d.Amount); err != nil {
+			return Discount{}, fmt.Errorf("failed to unmarshal discount '%s', %w", v.Description, err)
+		}
+	}
+	if len(v.Minimum) > 0 {
+		d.Minimum = currency.Clone(0)
+		if err := json.Unmarshal(v.Minimum, &d.Minimum); err != nil {
+			return Discount{}, fmt.Errorf("failed to unmarshal discount '%s', %w", v.Description, err)
+		}
+	}
+	return d, nil
+}
+
+// DiscountLine is the amount a discount took off a price. The amount is taken
+// off the net for discounts before tax, and off the gross for discounts after
+// tax.
+type DiscountLine struct {
+	Discount Discount // The definition of the discount.
+	Amount   Money    // The amount of the discount.
+}
+
+// discounts is the breakdown of discounts in the order they were applied.
+type discounts []DiscountLine
+
+// Add adds a discount to the collection. If the same discount has already
+// been applied the money value will be added to it. This method returns a new
+// slice to make sure this operation is immutable across price types.
+func (d discounts) add(v Discount, m Money) discounts {
+	result := make(discounts, len(d), len(d)+1)
+	copy(result, d)
+
+	if i := d.index(v); i >= 0 {
+		result[i].Amount = result[i].Amount.Add(m)
+	} else {
+		result = append(result, DiscountLine{Discount: v.clone(), Amount: m})
+	}
+
+	return result
+}
+
+// AddE is a checked version of add.
+func (d discounts) addE(v Discount, m Money) (discounts, error) {
+	if i := d.index(v); i >= 0 {
+		if _, err := d[i].Amount.AddE(m); err != nil {
+			return nil, err
+		}
+	}
+	return d.add(v, m), nil
+}
+
+// Index returns the index of the line holding the same discount or -1 if
+// there isn't one.
+func (d discounts) index(v Discount) int {
+	key := v.key()
+	return slices.IndexFunc(d, func(l DiscountLine) bool { return l.Discount.key() == key })
+}
+
+// Mul multiplies all the amounts in the collection by the passed amount.
+func (d discounts) mul(n int64) discounts {
+	if d == nil {
+		return nil
+	}
+	result := make(discounts, len(d))
+
+	for i, l := range d {
+		result[i] = DiscountLine{Discount: l.Discount, Amount: l.Amount.Mul(n)}
+	}
+
+	return result
+}
+
+// Neg negates all the amounts in the collection.
+func (d discounts) neg() discounts {
+	if d == nil {
+		return nil
+	}
+	result := make(discounts, len(d))
+
+	for i, l := range d {
+		result[i] = DiscountLine{Discount: l.Discount, Amount: l.Amount.FlipSign()}
+	}
+
+	return result
+}
+
+// total returns the sum of the discounts, in the currency of the passed money
+// object.
+func (d discounts) total(currency Money) Money {
+	total := currency.Clone(0)
+	for _, l := range d {
+		total = total.Add(l.Amount)
+	}
+	return total
+}
+
+// marshal returns the JSON representation of the discounts with their total.
+func (d discounts) marshal(currency Money) ([]byte, error) {
+	lines := make([]discountJSON, 0, len(d))
+
+	for _, l := range d {
+		v := l.Discount.toJSON()
+		v.Amount = json.RawMessage(fmt.Sprintf("%q", l.Amount.String()))
+		lines = append(lines, v)
+	}
+
+	detail, err := json.Marshal(lines)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	json := fmt.Sprintf(
+		`{"total": "%s", "detail": %s}`,
+		d.total(currency),
+		detail,
+	)
+
+	return []byte(json), nil
+}
+
+// unmarshal parses the JSON representation of the discounts. Bare amount
+// strings are parsed using the currency of the passed money object.
+func (d *discounts) unmarshal(b []byte, currency Money) error {
+	var v struct {
+		Total  json.RawMessage `json:"total"`
+		Detail []discountJSON  `json:"detail"`
+	}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal discounts, %w", err)
+	}
+
+	var result discounts
+
+	for _, e := range v.Detail {
+		m := currency.Clone(0)
+		if err := json.Unmarshal(e.Amount, &m); err != nil {
+			return fmt.Errorf("failed to unmarshal discount '%s', %w", e.Description, err)
+		}
+		if m.currency.Code != currency.currency.Code {
+			return fmt.Errorf("failed to unmarshal discount '%s', currency '%s' does not match '%s'", e.Description, m.currency.Code, currency.currency.Code)
+		}
+		discount, err := e.discount(m)
+		if err != nil {
+			return err
+		}
+		result = result.add(discount, m)
+	}
+
+	if len(v.Total) > 0 {
+		total := currency.Clone(0)
+		if err := json.Unmarshal(v.Total, &total); err != nil {
+			return fmt.Errorf("failed to unmarshal discount total, %w", err)
+		}
+		if sum := result.total(currency); total.currency.Code != sum.currency.Code || total.value != sum.value {
+			return fmt.Errorf("failed to unmarshal discounts, total %s does not equal the sum of the detail %s", total, sum)
+		}
+	}
+
+	*d = result
+	return nil
+}
+
+// AllocateDiscount applies an order level discount to a list of prices. The
+// discount is calculated once on the total of the prices, before or after tax,
+// then allocated across them in proportion to their net or gross using the
+// same lossless maths as Allocate. The taxes of each price are recalculated on
+// its discounted amount and each price records its share of the discount.
+// A minimum is compared to the total. The prices must be in the same currency
+// and not negative.
+func AllocateDiscount(prices []Price, d Discount) ([]Price, error) {
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("failed to allocate discount '%s', no prices passed", d.Description)
+	}
+
+	bases := make([]Money, len(prices))
+	total := prices[0].gross.Clone(0)
+	for i, p := range prices {
+		if err := checkSameMoneyCurrency(total, p.gross); err != nil {
+			return nil, fmt.Errorf("failed to allocate discount '%s', %w", d.Description, err)
+		}
+		bases[i] = p.discountBase(d.Timing)
+		if bases[i].IsNeg() {
+			return nil, fmt.Errorf("failed to allocate discount '%s', price %s is negative", d.Description, bases[i])
+		}
+		var err error
+		if total, err = total.AddE(bases[i]); err != nil {
+			return nil, fmt.Errorf("failed to allocate discount '%s', %w", d.Description, err)
+		}
+	}
+	if err := d.check(total); err != nil {
+		return nil, err
+	}
+	amount, err := d.calculate(total)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Price, len(prices))
+	copy(result, prices)
+	if amount.IsZero() {
+		return result, nil
+	}
+
+	// Only prices with something to discount share the discount, so the
+	// remainder is never allocated to a price that can't take it.
+	var ratios []int64
+	var indexes []int
+	for i, b := range bases {
+		if b.value > 0 {
+			ratios = append(ratios, b.value)
+			indexes = append(indexes, i)
+		}
+	}
+	shares, err := amount.allocate(ratios...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate discount '%s', %w", d.Description, err)
+	}
+	for i, s := range shares {
+		if err := result[indexes[i]].discount(d, s); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,389 @@
+package mongo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func taxedGBP(t *testing.T, net int64) Price {
+	t.Helper()
+	vat, _ := NetTax("VAT", "20")
+	p, _ := PriceFromSubunits("GBP", net, nil)
+	if err := p.AddTaxes(vat); err != nil {
+		t.Fatalf("AddTaxes failed: %s", err)
+	}
+	return p
+}
+
+func TestDiscountLabels(t *testing.T) {
+	sale, _ := PercentDiscount("Spring sale", "12.5")
+	assert(t, sale.Label() == "Spring sale 12.5%")
+	assert(t, sale.String() == "Spring sale 12.5% DiscountPercent DiscountBeforeTax")
+
+	m, _ := MoneyGBP(500)
+	voucher := AmountDiscount("Voucher", m)
+	voucher.Timing = DiscountAfterTax
+	voucher.Minimum, _ = MoneyGBP(5000)
+	assert(t, voucher.Label() == "Voucher £5.00")
+	assert(t, voucher.String() == "Voucher £5.00 DiscountAmount DiscountAfterTax over £50.00")
+
+	assert(t, OverrideDiscount("Clearance", m).Label() == "Clearance £5.00")
+}
+
+func TestDiscountTextMarshalling(t *testing.T) {
+	for _, k := range []DiscountKind{DiscountPercent, DiscountAmount, DiscountOverride} {
+		b, err := k.MarshalText()
+		assert(t, err == nil)
+		var result DiscountKind
+		assert(t, result.UnmarshalText(b) == nil && result == k)
+	}
+	for _, timing := range []DiscountTiming{DiscountBeforeTax, DiscountAfterTax} {
+		b, err := timing.MarshalText()
+		assert(t, err == nil)
+		var result DiscountTiming
+		assert(t, result.UnmarshalText(b) == nil && result == timing)
+	}
+
+	_, err := DiscountKind(9).MarshalText()
+	assert(t, err != nil)
+	_, err = DiscountTiming(9).MarshalText()
+	assert(t, err != nil)
+
+	var k DiscountKind
+	assert(t, k.UnmarshalText([]byte("free")) != nil)
+	var timing DiscountTiming
+	assert(t, timing.UnmarshalText([]byte("during")) != nil)
+}
+
+func TestPercentDiscountBeforeTax(t *testing.T) {
+	d, _ := PercentDiscount("Spring sale", "10")
+
+	p := taxedGBP(t, 10000)
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Net(), 9000)
+	assertMoneyValue(t, p.Tax(), 1800)
+	assertMoneyValue(t, p.Gross(), 10800)
+	assertMoneyValue(t, p.Discount(), 1000)
+
+	lines := p.Discounts()
+	assert(t, len(lines) == 1)
+	assert(t, lines[0].Discount.Label() == "Spring sale 10%")
+	assertMoneyValue(t, lines[0].Amount, 1000)
+	assertMoneyValue(t, p.TaxLines()[0].Amount, 1800)
+}
+
+func TestAmountDiscountAfterTax(t *testing.T) {
+	m, _ := MoneyGBP(1200)
+	d := AmountDiscount("Voucher", m)
+	d.Timing = DiscountAfterTax
+
+	p := taxedGBP(t, 10000)
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Gross(), 10800)
+	assertMoneyValue(t, p.Tax(), 1800)
+	assertMoneyValue(t, p.Net(), 9000)
+	assertMoneyValue(t, p.Discount(), 1200)
+	assert(t, p.Taxes()[0].Mode == TaxExclusive)
+}
+
+func TestDiscountLegacyInclusiveTaxes(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 100000, nil)
+	p.IncludeTaxPercent(20, "VAT")
+	p.IncludeTaxPercent(5, "Levy")
+	assertMoneyValue(t, p.Net(), 79365)
+
+	m, _ := MoneyGBP(1)
+	d := AmountDiscount("Rounding", m)
+	d.Timing = DiscountAfterTax
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Gross(), 99999)
+	assertMoneyValue(t, p.Net(), 79365)
+
+	lines := p.TaxLines()
+	assert(t, lines[0].Tax.Description == "Levy")
+	assertMoneyValue(t, lines[0].Amount, 3968)
+	assertMoneyValue(t, lines[1].Amount, 16666)
+
+	p, _ = PriceFromSubunits("GBP", 100000, nil)
+	p.IncludeTaxPercent(20, "VAT")
+	p.IncludeTaxPercent(5, "Levy")
+	d.Timing = DiscountBeforeTax
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Net(), 79364)
+	assertMoneyValue(t, p.Gross(), 99998)
+
+	gst, _ := NetTax("GST", "5")
+	p, _ = PriceFromSubunits("GBP", 100000, nil)
+	assert(t, p.IncludeTaxes(gst) == nil)
+	assert(t, p.IncludeTaxDecimal("20", "VAT") != nil)
+	assert(t, len(p.TaxLines()) == 1)
+}
+
+func TestOverrideDiscount(t *testing.T) {
+	m, _ := MoneyGBP(8000)
+	d := OverrideDiscount("Clearance", m)
+
+	p := taxedGBP(t, 10000)
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Net(), 8000)
+	assertMoneyValue(t, p.Tax(), 1600)
+	assertMoneyValue(t, p.Discount(), 2000)
+
+	// A lower price isn't raised.
+	p = taxedGBP(t, 5000)
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Net(), 5000)
+	assertMoneyValue(t, p.Discount(), 0)
+	assert(t, len(p.Discounts()) == 0)
+}
+
+func TestAmountDiscountIsCapped(t *testing.T) {
+	m, _ := MoneyGBP(500)
+	p := taxedGBP(t, 300)
+	assert(t, p.ApplyDiscounts(AmountDiscount("Voucher", m)) == nil)
+	assertMoneyValue(t, p.Gross(), 0)
+	assertMoneyValue(t, p.Tax(), 0)
+	assertMoneyValue(t, p.Discount(), 300)
+}
+
+func TestDiscountMinimum(t *testing.T) {
+	m, _ := MoneyGBP(500)
+	d := AmountDiscount("£5 off orders over £50", m)
+	d.Minimum, _ = MoneyGBP(5000)
+
+	p := taxedGBP(t, 4000)
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Net(), 4000)
+	assert(t, len(p.Discounts()) == 0)
+
+	p = taxedGBP(t, 6000)
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Net(), 5500)
+	assertMoneyValue(t, p.Tax(), 1100)
+}
+
+func TestDiscountKeepsPerUnitTaxes(t *testing.T) {
+	deposit, _ := MoneyGBP(10)
+	vat, _ := NetTax("VAT", "20")
+	p, _ := PriceFromSubunits("GBP", 100, nil)
+	assert(t, p.AddTaxes(UnitTax("Deposit", deposit), vat) == nil)
+	p = p.Mul(3)
+
+	d, _ := PercentDiscount("Multibuy", "50")
+	assert(t, p.ApplyDiscounts(d) == nil)
+	assertMoneyValue(t, p.Net(), 150)
+	assertMoneyValue(t, taxAmount(p.taxes.detail, "Deposit"), 30)
+	assertMoneyValue(t, taxAmount(p.taxes.detail, "VAT"), 30)
+	assertMoneyValue(t, p.Gross(), 210)
+}
+
+func TestDiscountsCompound(t *testing.T) {
+	sale, _ := PercentDiscount("Sale", "10")
+	m, _ := MoneyGBP(1000)
+	voucher := AmountDiscount("Voucher", m)
+	voucher.Timing = DiscountAfterTax
+
+	p := taxedGBP(t, 10000)
+	assert(t, p.ApplyDiscounts(sale, voucher) == nil)
+	assertMoneyValue(t, p.Gross(), 9800)
+	assertMoneyValue(t, p.Tax(), 1633)
+	assertMoneyValue(t, p.Net(), 8167)
+	assertMoneyValue(t, p.Discount(), 2000)
+	assert(t, len(p.Discounts()) == 2)
+
+	// The same discount applied twice is merged.
+	assert(t, p.ApplyDiscounts(sale) == nil)
+	assert(t, len(p.Discounts()) == 2)
+	assertMoneyValue(t, p.Discounts()[0].Amount, 1817)
+}
+
+func TestDiscountErrors(t *testing.T) {
+	p := taxedGBP(t, 10000)
+
+	over, _ := PercentDiscount("Too much", "10")
+	over.Rate.SetInt64(101)
+	eur, _ := MoneyEUR(100)
+	m, _ := MoneyGBP(100)
+	unknown := AmountDiscount("Unknown", m)
+	unknown.Kind = 5
+	timing := AmountDiscount("Timing", m)
+	timing.Timing = 5
+	neg, _ := MoneyGBP(-100)
+	ok, _ := PercentDiscount("Fine", "10")
+
+	bad := []Discount{
+		over,
+		{Description: "No rate", Kind: DiscountPercent},
+		{Description: "No amount", Kind: DiscountAmount},
+		AmountDiscount("Negative", neg),
+		unknown,
+		timing,
+	}
+	for _, d := range bad {
+		assert(t, p.ApplyDiscounts(ok, d) != nil)
+	}
+	err := p.ApplyDiscounts(ok, AmountDiscount("Euros", eur))
+	assert(t, errors.Is(err, ErrCurrencyMismatch))
+	assertMoneyValue(t, p.Gross(), 12000)
+	assert(t, len(p.Discounts()) == 0)
+
+	_, err = PercentDiscount("Bad", "abc")
+	assert(t, err != nil)
+	_, err = PercentDiscount("Bad", "-1")
+	assert(t, err != nil)
+	_, err = PercentDiscount("Bad", "50/3")
+	assert(t, err != nil)
+	_, err = PercentDiscount("Bad", "1e1")
+	assert(t, err != nil)
+
+	p = p.Neg()
+	assert(t, p.ApplyDiscounts(ok) != nil)
+}
+
+func TestPriceArithmeticKeepsDiscounts(t *testing.T) {
+	d, _ := PercentDiscount("Sale", "10")
+	p := taxedGBP(t, 1000)
+	assert(t, p.ApplyDiscounts(d) == nil)
+
+	sum := p.Add(p)
+	assertMoneyValue(t, sum.Discount(), 200)
+	assert(t, len(sum.Discounts()) == 1)
+	assertMoneyValue(t, sum.Sub(p).Discount(), 100)
+	assertMoneyValue(t, p.Mul(3).Discount(), 300)
+	assertMoneyValue(t, p.Neg().Discount(), -100)
+
+	parts := p.Mul(2).Split(3)
+	assertMoneyValue(t, parts[0].Discount(), 67)
+	assertMoneyValue(t, parts[1].Discount(), 67)
+	assertMoneyValue(t, parts[2].Discount(), 66)
+
+	e, err := p.AddE(p)
+	assert(t, err == nil)
+	assertMoneyValue(t, e.Discount(), 200)
+	e, err = p.SubE(p)
+	assert(t, err == nil)
+	assertMoneyValue(t, e.Discount(), 0)
+	e, err = p.MulE(4)
+	assert(t, err == nil)
+	assertMoneyValue(t, e.Discount(), 400)
+	e, err = p.NegE()
+	assert(t, err == nil)
+	assertMoneyValue(t, e.Discount(), -100)
+}
+
+func TestDiscountJsonRoundTrip(t *testing.T) {
+	sale, _ := PercentDiscount("Sale", "10")
+	m, _ := MoneyGBP(500)
+	voucher := AmountDiscount("Voucher", m)
+	voucher.ID = "V1"
+	voucher.Timing = DiscountAfterTax
+	voucher.Minimum, _ = MoneyGBP(5000)
+
+	p := taxedGBP(t, 10000)
+	assert(t, p.ApplyDiscounts(sale, voucher) == nil)
+
+	b, err := json.Marshal(p)
+	assert(t, err == nil)
+	assertJSON(t, b, `{"currency":"GBP","gross":"£103.00","net":"£85.83","tax":{"total":"£17.17","detail":[{"description":"VAT","label":"VAT 20%","base":"net","mode":"exclusive","rate":"20","amount":"£17.17"}]},"discount":{"total":"£15.00","detail":[{"description":"Sale","label":"Sale 10%","kind":"percent","timing":"before","rate":"10","amount":"£10.00"},{"id":"V1","description":"Voucher","label":"Voucher £5.00","kind":"amount","timing":"after","fixed":{"currency":"GBP","amount":"£5.00"},"minimum":{"currency":"GBP","amount":"£50.00"},"amount":"£5.00"}]}}`)
+
+	var result Price
+	assert(t, json.Unmarshal(b, &result) == nil)
+	assertMoneyValue(t, result.Discount(), 1500)
+	lines := result.Discounts()
+	assert(t, len(lines) == 2)
+	assert(t, lines[1].Discount.ID == "V1" && lines[1].Discount.Timing == DiscountAfterTax)
+	assertMoneyValue(t, lines[1].Discount.Minimum, 5000)
+
+	again, err := json.Marshal(result)
+	assert(t, err == nil)
+	assert(t, string(again) == string(b))
+
+	d, err := json.Marshal(voucher)
+	assert(t, err == nil)
+	var v Discount
+	assert(t, json.Unmarshal(d, &v) == nil)
+	assert(t, v.String() == voucher.String())
+
+	wrong := `{"currency":"GBP","gross":"£103.00","discount":{"total":"£1.00","detail":[{"description":"Sale","kind":"percent","timing":"before","rate":"10","amount":"£10.00"}]}}`
+	assert(t, json.Unmarshal([]byte(wrong), &result) != nil)
+}
+
+func TestAllocateDiscount(t *testing.T) {
+	m, _ := MoneyGBP(1000)
+	d := AmountDiscount("£10 off orders over £50", m)
+	d.Minimum, _ = MoneyGBP(5000)
+
+	prices := []Price{taxedGBP(t, 1000), taxedGBP(t, 2000), taxedGBP(t, 0), taxedGBP(t, 3000)}
+	result, err := AllocateDiscount(prices, d)
+	assert(t, err == nil)
+
+	assertMoneyValue(t, result[0].Discount(), 167)
+	assertMoneyValue(t, result[1].Discount(), 333)
+	assertMoneyValue(t, result[2].Discount(), 0)
+	assertMoneyValue(t, result[3].Discount(), 500)
+
+	assertMoneyValue(t, result[0].Net(), 833)
+	assertMoneyValue(t, result[0].Tax(), 167)
+	assertMoneyValue(t, result[1].Net(), 1667)
+	assertMoneyValue(t, result[1].Tax(), 333)
+	assertMoneyValue(t, result[3].Net(), 2500)
+	assertMoneyValue(t, result[3].Tax(), 500)
+
+	// The original prices are unchanged.
+	assertMoneyValue(t, prices[0].Net(), 1000)
+	assert(t, len(prices[0].Discounts()) == 0)
+
+	// The minimum is compared to the total.
+	result, err = AllocateDiscount(prices[:2], d)
+	assert(t, err == nil)
+	assertMoneyValue(t, result[0].Discount(), 0)
+	assertMoneyValue(t, result[1].Discount(), 0)
+
+	// A price with nothing to discount never takes the remainder.
+	m, _ = MoneyGBP(1)
+	zero, _ := PriceFromSubunits("GBP", 0, nil)
+	three, _ := PriceFromSubunits("GBP", 3, nil)
+	result, err = AllocateDiscount([]Price{zero, three, three}, AmountDiscount("Penny", m))
+	assert(t, err == nil)
+	assertMoneyValue(t, result[0].Gross(), 0)
+	assertMoneyValue(t, result[0].Discount(), 0)
+	assertMoneyValue(t, result[1].Discount(), 1)
+	assertMoneyValue(t, result[2].Discount(), 0)
+}
+
+func TestAllocatePercentDiscountAfterTax(t *testing.T) {
+	d, _ := PercentDiscount("Staff", "15")
+	d.Timing = DiscountAfterTax
+
+	prices := []Price{taxedGBP(t, 333), taxedGBP(t, 333), taxedGBP(t, 334)}
+	result, err := AllocateDiscount(prices, d)
+	assert(t, err == nil)
+
+	var total int64
+	for _, p := range result {
+		total += p.Discount().Value()
+	}
+	assertValue(t, total, 180)
+	assertMoneyValue(t, result[0].Gross(), 340)
+	assertMoneyValue(t, result[1].Gross(), 340)
+	assertMoneyValue(t, result[2].Gross(), 341)
+}
+
+func TestAllocateDiscountErrors(t *testing.T) {
+	d, _ := PercentDiscount("Sale", "10")
+
+	_, err := AllocateDiscount(nil, d)
+	assert(t, err != nil)
+
+	eur, _ := PriceFromSubunits("EUR", 100, nil)
+	_, err = AllocateDiscount([]Price{taxedGBP(t, 100), eur}, d)
+	assert(t, errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = AllocateDiscount([]Price{taxedGBP(t, 100), taxedGBP(t, 100).Neg()}, d)
+	assert(t, err != nil)
+
+	d.Kind = 5
+	_, err = AllocateDiscount([]Price{taxedGBP(t, 100)}, d)
+	assert(t, err != nil)
+}
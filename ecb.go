@@ -0,0 +1,133 @@
+package mongo
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ecbBase is the base currency of all European Central Bank reference rates.
+const ecbBase = "EUR"
+
+// ecbEnvelope is the structure of the European Central Bank XML reference
+// rates files, e.g. eurofxref-daily.xml and eurofxref-hist.xml.
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// LoadECBXML reads exchange rates from a European Central Bank XML reference
+// rates file on disk.
+func LoadECBXML(path string) ([]ExchangeRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exchange rates, %w", err)
+	}
+	defer f.Close()
+	return ReadECBXML(f)
+}
+
+// LoadECBCSV reads exchange rates from a European Central Bank CSV reference
+// rates file on disk.
+func LoadECBCSV(path string) ([]ExchangeRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exchange rates, %w", err)
+	}
+	defer f.Close()
+	return ReadECBCSV(f)
+}
+
+// ReadECBXML reads exchange rates in the European Central Bank XML reference
+// rates format. All rates have a base currency of EUR and are dated.
+func ReadECBXML(r io.Reader) ([]ExchangeRate, error) {
+	var env ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&env); err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates, %w", err)
+	}
+
+	var rates []ExchangeRate
+	for _, day := range env.Days {
+		date, err := parseECBDate(day.Time)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range day.Rates {
+			rate, err := NewExchangeRate(ecbBase, r.Currency, r.Rate)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read exchange rates, %w", err)
+			}
+			rate.Date = date
+			rates = append(rates, rate)
+		}
+	}
+	return rates, nil
+}
+
+// ReadECBCSV reads exchange rates in the European Central Bank CSV reference
+// rates format. The first column contains the date and the rest contain the
+// rates of the currencies named in the header. Missing rates, marked as "N/A",
+// are skipped. All rates have a base currency of EUR and are dated.
+func ReadECBCSV(r io.Reader) ([]ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates, %w", err)
+	}
+	if len(header) == 0 || !strings.EqualFold(strings.TrimSpace(header[0]), "Date") {
+		return nil, fmt.Errorf("failed to read exchange rates, the first column must be 'Date'")
+	}
+
+	var rates []ExchangeRate
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read exchange rates, %w", err)
+		}
+
+		date, err := parseECBDate(record[0])
+		if err != nil {
+			return nil, err
+		}
+		for i, value := range record[1:] {
+			code, value := strings.TrimSpace(header[i+1]), strings.TrimSpace(value)
+			if code == "" || value == "" || value == "N/A" {
+				continue
+			}
+			rate, err := NewExchangeRate(ecbBase, code, value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read exchange rates, %w", err)
+			}
+			rate.Date = date
+			rates = append(rates, rate)
+		}
+	}
+	return rates, nil
+}
+
+// parseECBDate parses the dates used by European Central Bank reference rates
+// files, e.g. "2024-01-05" or "05 January 2024".
+func parseECBDate(str string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	for _, layout := range []string{"2006-01-02", "02 January 2006", "2 January 2006"} {
+		if date, err := time.Parse(layout, str); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to read exchange rates, '%s' is not a valid date", str)
+}
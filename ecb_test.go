@@ -0,0 +1,107 @@
+package mongo
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ecbXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time='2024-01-05'>
+			<Cube currency='USD' rate='1.0921'/>
+			<Cube currency='JPY' rate='158.08'/>
+			<Cube currency='GBP' rate='0.86053'/>
+		</Cube>
+		<Cube time='2024-01-04'>
+			<Cube currency='USD' rate='1.0953'/>
+			<Cube currency='JPY' rate='157.77'/>
+			<Cube currency='GBP' rate='0.86165'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+const ecbCSV = `Date, USD, JPY, CYP, GBP, 
+05 January 2024, 1.0921, 158.08, N/A, 0.86053, 
+`
+
+const ecbHistCSV = `Date,USD,JPY,CYP,GBP,
+2024-01-05,1.0921,158.08,N/A,0.86053,
+2024-01-04,1.0953,157.77,N/A,0.86165,
+`
+
+func TestReadECBXML(t *testing.T) {
+	rates, err := ReadECBXML(strings.NewReader(ecbXML))
+	if err != nil {
+		t.Fatalf("ReadECBXML failed: %s", err)
+	}
+	assert(t, len(rates) == 6)
+	assert(t, rates[0].String() == "EUR/USD 1.0921")
+	assert(t, rates[0].Date.Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)))
+	assert(t, rates[5].String() == "EUR/GBP 0.86165")
+
+	// The latest rates are used.
+	p, _ := NewMemoryRates(rates...)
+	r, _ := p.Rate("EUR", "USD")
+	assert(t, r.Rate.Cmp(big.NewRat(10921, 10000)) == 0)
+
+	m, _ := MoneyEUR(1000)
+	m, err = Convert(m, "USD", p)
+	assert(t, err == nil)
+	assertMoneyString(t, m, "USD", "$10.92")
+
+	_, err = ReadECBXML(strings.NewReader("<Envelope><Cube><Cube time='x'/></Cube></Envelope>"))
+	assert(t, err != nil)
+	_, err = ReadECBXML(strings.NewReader("<Envelope>"))
+	assert(t, err != nil)
+}
+
+func TestReadECBCSV(t *testing.T) {
+	rates, err := ReadECBCSV(strings.NewReader(ecbCSV))
+	if err != nil {
+		t.Fatalf("ReadECBCSV failed: %s", err)
+	}
+	assert(t, len(rates) == 3)
+	assert(t, rates[2].String() == "EUR/GBP 0.86053")
+	assert(t, rates[2].Date.Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)))
+
+	rates, err = ReadECBCSV(strings.NewReader(ecbHistCSV))
+	if err != nil {
+		t.Fatalf("ReadECBCSV failed: %s", err)
+	}
+	assert(t, len(rates) == 6)
+	assert(t, rates[3].String() == "EUR/USD 1.0953")
+
+	for _, str := range []string{"", "USD,JPY\n1,2\n", "Date,USD\nyesterday,1\n", "Date,USD\n2024-01-05,abc\n"} {
+		if _, err := ReadECBCSV(strings.NewReader(str)); err == nil {
+			t.Errorf("ReadECBCSV failed to error on %q", str)
+		}
+	}
+}
+
+func TestLoadECB(t *testing.T) {
+	dir := t.TempDir()
+	xmlPath := filepath.Join(dir, "eurofxref.xml")
+	csvPath := filepath.Join(dir, "eurofxref.csv")
+	os.WriteFile(xmlPath, []byte(ecbXML), 0o600)
+	os.WriteFile(csvPath, []byte(ecbCSV), 0o600)
+
+	rates, err := LoadECBXML(xmlPath)
+	assert(t, err == nil && len(rates) == 6)
+
+	rates, err = LoadECBCSV(csvPath)
+	assert(t, err == nil && len(rates) == 3)
+
+	_, err = LoadECBXML(filepath.Join(dir, "missing.xml"))
+	assert(t, err != nil)
+	_, err = LoadECBCSV(filepath.Join(dir, "missing.csv"))
+	assert(t, err != nil)
+}
@@ -0,0 +1,26 @@
+package mongo
+
+import "errors"
+
+var (
+	// ErrCurrencyMismatch is returned when an operation is performed on money
+	// objects containing different currencies.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	// ErrOverflow is returned when the result of an operation can't be
+	// represented by the money object.
+	ErrOverflow = errors.New("integer overflow")
+
+	// ErrDivideByZero is returned when an operation would divide by zero.
+	ErrDivideByZero = errors.New("divide by zero")
+
+	// ErrAmbiguousCurrency is returned when a currency symbol matches more than
+	// one currency.
+	ErrAmbiguousCurrency = errors.New("ambiguous currency")
+
+	// ErrNoRate is returned when an exchange rate isn't available.
+	ErrNoRate = errors.New("exchange rate not found")
+
+	// ErrNoTaxRate is returned when a tax rate isn't available.
+	ErrNoTaxRate = errors.New("tax rate not found")
+)
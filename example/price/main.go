@@ -10,7 +10,7 @@ import (
 
 func main() {
 
-	m, err := mongo.PriceGBP(1055, 17.5)
+	m, err := mongo.PriceGBP(1055, 20)
 
 	if err != nil {
 		log.Fatal("Error occured creating price")
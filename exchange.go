@@ -0,0 +1,284 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// ExchangeRate is the price of one unit of the base currency expressed in the
+// quote currency, e.g. a EUR/USD rate of 1.0921 means one euro buys 1.0921 US
+// dollars. The rate is held as an exact rational number.
+type ExchangeRate struct {
+	Base  string         // The ISO 4217 code of the base currency.
+	Quote string         // The ISO 4217 code of the quote currency.
+	Rate  *big.Rat       // The number of quote currency units per base currency unit.
+	Date  time.Time      // The date the rate applies to, or the zero time if unknown.
+	Legs  []ExchangeRate // The rates this rate was derived from by triangulation or interpolation, if any.
+}
+
+// NewExchangeRate constructs a new exchange rate from a decimal number
+// expressed as a string, e.g. "1.0921".
+func NewExchangeRate(base, quote, rate string) (ExchangeRate, error) {
+	r, err := parseDecimal(rate)
+	if err != nil {
+		return ExchangeRate{}, fmt.Errorf("failed to create %s/%s exchange rate, %w", base, quote, err)
+	}
+	e := ExchangeRate{
+		Base:  base,
+		Quote: quote,
+		Rate:  r,
+	}
+	return e, e.validate()
+}
+
+// Inverse returns the exchange rate from the quote currency to the base
+// currency.
+func (e ExchangeRate) Inverse() ExchangeRate {
+	e.Base, e.Quote = e.Quote, e.Base
+	e.Rate = new(big.Rat).Inv(e.Rate)
+	return e
+}
+
+// String is an implementation of fmt.Stringer, e.g. "EUR/USD 1.0921".
+func (e ExchangeRate) String() string {
+	return fmt.Sprintf("%s/%s %s", e.Base, e.Quote, ratString(e.Rate))
+}
+
+// validate checks the exchange rate can be used to convert money.
+func (e ExchangeRate) validate() error {
+	if e.Base == "" || e.Quote == "" {
+		return fmt.Errorf("the exchange rate %s/%s has no base or quote currency", e.Base, e.Quote)
+	}
+	if e.Rate == nil || e.Rate.Sign() <= 0 {
+		return fmt.Errorf("the exchange rate %s/%s must be greater than zero", e.Base, e.Quote)
+	}
+	return nil
+}
+
+// RateProvider is implemented by anything that can supply exchange rates.
+type RateProvider interface {
+	// Rate returns the exchange rate from the base currency to the quote
+	// currency. An error wrapping ErrNoRate is returned if the rate isn't
+	// available.
+	Rate(base, quote string) (ExchangeRate, error)
+}
+
+// Conversion is the result of converting money to another currency.
+type Conversion struct {
+	Money Money        // The converted money.
+	Rate  ExchangeRate // The exchange rate used, including its date.
+}
+
+// Convert converts money to another currency using an exchange rate supplied
+// by the provider. The result is calculated exactly, taking into account the
+// subunits of both currencies, and then rounded once using the money's
+// rounding function.
+func Convert(m Money, to string, provider RateProvider) (Money, error) {
+	c, err := ConvertDetailed(m, to, provider)
+	return c.Money, err
+}
+
+// ConvertDetailed converts money to another currency in the same way as
+// Convert, also returning the exchange rate that was used.
+func ConvertDetailed(m Money, to string, provider RateProvider) (Conversion, error) {
+	curr, rate, factor, err := conversionRate(m.currency, to, provider)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	value := roundRat(factor.Mul(factor, new(big.Rat).SetInt64(m.value)), m.round)
+	if !value.IsInt64() {
+		return Conversion{}, fmt.Errorf("failed to convert %s to %s, %w", m, to, ErrOverflow)
+	}
+
+	m.currency = curr
+	m.value = value.Int64()
+	return Conversion{Money: m, Rate: rate}, nil
+}
+
+// ConvertBig converts big money to another currency using an exchange rate
+// supplied by the provider in the same way as Convert.
+func ConvertBig(b BigMoney, to string, provider RateProvider) (BigMoney, error) {
+	curr, _, factor, err := conversionRate(b.currency, to, provider)
+	if err != nil {
+		return BigMoney{}, err
+	}
+
+	b.currency = curr
+	b.value = roundRat(factor.Mul(factor, new(big.Rat).SetInt(b.int())), b.round)
+	return b, nil
+}
+
+// conversionRate returns the target currency, the exchange rate and the factor
+// to multiply the subunits of the source currency by to get the subunits of
+// the target.
+func conversionRate(from Currency, to string, provider RateProvider) (Currency, ExchangeRate, *big.Rat, error) {
+	curr, err := lookupCurrency(to)
+	if err != nil {
+		return Currency{}, ExchangeRate{}, nil, fmt.Errorf("failed to convert %s to %s, %w", from.Code, to, err)
+	}
+	if from.Code == to {
+		return curr, ExchangeRate{Base: to, Quote: to, Rate: big.NewRat(1, 1)}, big.NewRat(1, 1), nil
+	}
+
+	rate, err := provider.Rate(from.Code, to)
+	if err != nil {
+		return Currency{}, ExchangeRate{}, nil, fmt.Errorf("failed to convert %s to %s, %w", from.Code, to, err)
+	}
+	if rate.Base != from.Code || rate.Quote != to {
+		return Currency{}, ExchangeRate{}, nil, fmt.Errorf("failed to convert %s to %s, the provider returned a %s/%s rate", from.Code, to, rate.Base, rate.Quote)
+	}
+	if err := rate.validate(); err != nil {
+		return Currency{}, ExchangeRate{}, nil, fmt.Errorf("failed to convert %s to %s, %w", from.Code, to, err)
+	}
+
+	r := new(big.Rat).Set(rate.Rate)
+	exp := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt(curr.Subunits-from.Subunits))), nil)
+	if curr.Subunits >= from.Subunits {
+		r.Mul(r, new(big.Rat).SetInt(exp))
+	} else {
+		r.Quo(r, new(big.Rat).SetInt(exp))
+	}
+	return curr, rate, r, nil
+}
+
+// CrossRates is a rate provider that derives rates its underlying provider
+// doesn't supply by triangulating through a pivot currency, e.g. GBP/USD from
+// GBP/EUR and EUR/USD. Cross rates are exact and dated with the earliest date
+// of the rates they were derived from.
+type CrossRates struct {
+	Provider RateProvider // The provider of the underlying rates.
+	Pivot    string       // The ISO 4217 code of the pivot currency.
+}
+
+// Rate is an implementation of RateProvider.
+func (c CrossRates) Rate(base, quote string) (ExchangeRate, error) {
+	rate, err := c.Provider.Rate(base, quote)
+	if !errors.Is(err, ErrNoRate) || c.Pivot == "" || base == c.Pivot || quote == c.Pivot {
+		return rate, err
+	}
+
+	first, err := c.Provider.Rate(base, c.Pivot)
+	if err != nil {
+		return ExchangeRate{}, fmt.Errorf("no %s/%s rate via %s, %w", base, quote, c.Pivot, err)
+	}
+	second, err := c.Provider.Rate(c.Pivot, quote)
+	if err != nil {
+		return ExchangeRate{}, fmt.Errorf("no %s/%s rate via %s, %w", base, quote, c.Pivot, err)
+	}
+
+	rate = ExchangeRate{
+		Base:  base,
+		Quote: quote,
+		Rate:  new(big.Rat).Mul(first.Rate, second.Rate),
+		Date:  first.Date,
+		Legs:  []ExchangeRate{first, second},
+	}
+	if second.Date.Before(first.Date) {
+		rate.Date = second.Date
+	}
+	return rate, nil
+}
+
+// MemoryRates is a rate provider that holds exchange rates in memory. Inverse
+// rates are derived automatically. It's safe for concurrent use.
+type MemoryRates struct {
+	mu    sync.RWMutex
+	rates map[[2]string]ExchangeRate
+}
+
+// NewMemoryRates constructs a new in-memory rate provider containing the
+// passed rates.
+func NewMemoryRates(rates ...ExchangeRate) (*MemoryRates, error) {
+	p := &MemoryRates{
+		rates: make(map[[2]string]ExchangeRate, len(rates)),
+	}
+	for _, r := range rates {
+		if err := p.Set(r); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Set adds an exchange rate to the provider. If a rate for the same currency
+// pair is already held it's replaced, unless it has a later date.
+func (p *MemoryRates) Set(r ExchangeRate) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	r.Rate = new(big.Rat).Set(r.Rate)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rates == nil {
+		p.rates = make(map[[2]string]ExchangeRate)
+	}
+	key := [2]string{r.Base, r.Quote}
+	if existing, ok := p.rates[key]; ok && existing.Date.After(r.Date) {
+		return nil
+	}
+	p.rates[key] = r
+	return nil
+}
+
+// Rate is an implementation of RateProvider.
+func (p *MemoryRates) Rate(base, quote string) (ExchangeRate, error) {
+	if base == quote {
+		return ExchangeRate{Base: base, Quote: quote, Rate: big.NewRat(1, 1)}, nil
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if r, ok := p.rates[[2]string{base, quote}]; ok {
+		r.Rate = new(big.Rat).Set(r.Rate)
+		return r, nil
+	}
+	if r, ok := p.rates[[2]string{quote, base}]; ok {
+		return r.Inverse(), nil
+	}
+	return ExchangeRate{}, fmt.Errorf("no %s/%s rate, %w", base, quote, ErrNoRate)
+}
+
+// ratString returns a rational number as an exact decimal string if it has a
+// terminating decimal expansion, otherwise it's rounded to 18 decimal places.
+func ratString(r *big.Rat) string {
+	if r == nil {
+		return "<nil>"
+	}
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	// The expansion terminates if the denominator only has factors of 2 and 5.
+	d := new(big.Int).Set(r.Denom())
+	prec := 0
+	for _, f := range []int64{2, 5} {
+		n, rem, factor := 0, new(big.Int), big.NewInt(f)
+		for {
+			q, m := new(big.Int).QuoRem(d, factor, rem)
+			if m.Sign() != 0 {
+				break
+			}
+			d, n = q, n+1
+		}
+		if n > prec {
+			prec = n
+		}
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		prec = 18
+	}
+	return r.FloatString(prec)
+}
+
+// absInt returns the absolute value of an int.
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
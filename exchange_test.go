@@ -0,0 +1,119 @@
+package mongo
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestExchangeRate(t *testing.T) {
+	r, err := NewExchangeRate("EUR", "USD", "1.0921")
+	assert(t, err == nil)
+	assert(t, r.String() == "EUR/USD 1.0921")
+	assert(t, r.Rate.Cmp(big.NewRat(10921, 10000)) == 0)
+
+	i := r.Inverse()
+	assert(t, i.Base == "USD" && i.Quote == "EUR")
+	assert(t, i.Rate.Cmp(big.NewRat(10000, 10921)) == 0)
+	assert(t, r.Rate.Cmp(big.NewRat(10921, 10000)) == 0)
+
+	r, _ = NewExchangeRate("GBP", "EUR", "1.25")
+	assert(t, r.Inverse().String() == "EUR/GBP 0.8")
+
+	for _, rate := range []string{"0", "-1.5", "abc"} {
+		if _, err := NewExchangeRate("EUR", "USD", rate); err == nil {
+			t.Errorf("NewExchangeRate failed to error on rate '%s'", rate)
+		}
+	}
+	_, err = NewExchangeRate("", "USD", "1")
+	assert(t, err != nil)
+}
+
+func TestConvert(t *testing.T) {
+	p, err := NewMemoryRates(
+		ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(10921, 10000)},
+		ExchangeRate{Base: "EUR", Quote: "JPY", Rate: big.NewRat(15808, 100)},
+		ExchangeRate{Base: "EUR", Quote: "BHD", Rate: big.NewRat(4117, 10000)},
+	)
+	assert(t, err == nil)
+
+	tests := []struct {
+		from     string
+		value    int64
+		to       string
+		f        RoundingMode
+		expected int64
+	}{
+		{"EUR", 10000, "USD", nil, 10921},
+		{"EUR", 1055, "USD", nil, 1152},
+		{"EUR", 1055, "USD", RoundDown, 1152},
+		{"EUR", 1055, "USD", RoundUp, 1153},
+		{"USD", 10921, "EUR", nil, 10000},
+		{"EUR", 1055, "JPY", nil, 1668},
+		{"JPY", 15808, "EUR", nil, 10000},
+		{"EUR", 1055, "BHD", nil, 4343},
+		{"BHD", 4117, "EUR", nil, 1000},
+		{"EUR", -1055, "USD", nil, -1152},
+		{"EUR", 1055, "EUR", nil, 1055},
+	}
+	for _, test := range tests {
+		m, _ := MoneyFromSubunits(test.from, test.value, test.f)
+		c, err := Convert(m, test.to, p)
+		if err != nil {
+			t.Errorf("Convert %s to %s failed: %s", m, test.to, err)
+			continue
+		}
+		assert(t, c.IsoCode() == test.to)
+		assertMoneyValue(t, c, test.expected)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	p, _ := NewMemoryRates(ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(10921, 10000)})
+	m, _ := MoneyEUR(1055)
+
+	_, err := Convert(m, "GBP", p)
+	assert(t, errors.Is(err, ErrNoRate))
+
+	_, err = Convert(m, "XXX", p)
+	assert(t, err != nil)
+
+	m, _ = MoneyEUR(int64(9e18))
+	_, err = Convert(m, "USD", p)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	b, err := ConvertBig(m.Big(), "USD", p)
+	assert(t, err == nil)
+	assert(t, b.Value().String() == "9828900000000000000")
+}
+
+func TestMemoryRates(t *testing.T) {
+	p, _ := NewMemoryRates()
+
+	r, err := p.Rate("EUR", "EUR")
+	assert(t, err == nil && r.Rate.Cmp(big.NewRat(1, 1)) == 0)
+
+	_, err = p.Rate("EUR", "USD")
+	assert(t, errors.Is(err, ErrNoRate))
+
+	assert(t, p.Set(ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(11, 10)}) == nil)
+	r, _ = p.Rate("EUR", "USD")
+	assert(t, r.Rate.Cmp(big.NewRat(11, 10)) == 0)
+
+	// The returned rate can't modify the provider's rate.
+	r.Rate.SetInt64(5)
+	r, _ = p.Rate("USD", "EUR")
+	assert(t, r.Rate.Cmp(big.NewRat(10, 11)) == 0)
+
+	assert(t, p.Set(ExchangeRate{Base: "EUR", Quote: "USD"}) != nil)
+
+	var zero MemoryRates
+	assert(t, zero.Set(ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(11, 10)}) == nil)
+}
+
+func TestRatString(t *testing.T) {
+	assert(t, ratString(big.NewRat(10921, 10000)) == "1.0921")
+	assert(t, ratString(big.NewRat(1, 8)) == "0.125")
+	assert(t, ratString(big.NewRat(5, 1)) == "5")
+	assert(t, ratString(big.NewRat(1, 3)) == "0.333333333333333333")
+}
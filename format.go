@@ -1,183 +1,207 @@
 package mongo
 
-// Format represents the currency's currencyFormat.
-type currencyFormat struct {
-	code     string // The ISO 4217 currency code.
-	subunits int    // The number of subunits.
-	thouSep  string // The thousand separator.
-	subSep   string // The subunit separator.
-	template string // The string format template.
+// Currency is the definition of a currency and how to format it as a string.
+type Currency struct {
+	Code      string        // The ISO 4217 currency code.
+	Numeric   int           // The ISO 4217 numeric code, or zero if the currency doesn't have one.
+	Name      string        // The English name of the currency.
+	Exponent  int           // The ISO 4217 minor unit exponent, or -1 if not applicable.
+	Subunits  int           // The number of subunits.
+	ThouSep   string        // The thousand separator.
+	SubSep    string        // The subunit separator.
+	Template  string        // The string format template, where "0" is replaced by the value.
+	Negative  NegativeStyle // How negative values are presented.
+	Withdrawn bool          // Whether the currency has been withdrawn from use.
 }
 
-// CurrencyFormats contain a map of all recognised currency formats.
-var currencyFormats = map[string]currencyFormat{
-	"AED": {code: "AED", subunits: 2, thouSep: ",", subSep: ".", template: "0 د.إ"},
-	"AFN": {code: "AFN", subunits: 2, thouSep: ",", subSep: ".", template: "0 ؋"},
-	"ALL": {code: "ALL", subunits: 2, thouSep: ",", subSep: ".", template: "L0"},
-	"AMD": {code: "AMD", subunits: 2, thouSep: ",", subSep: ".", template: "0 ֏"},
-	"ANG": {code: "ANG", subunits: 2, thouSep: ".", subSep: ",", template: "ƒ0"},
-	"AOA": {code: "AOA", subunits: 2, thouSep: ",", subSep: ".", template: "0Kz"},
-	"ARS": {code: "ARS", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"AUD": {code: "AUD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"AWG": {code: "AWG", subunits: 2, thouSep: ",", subSep: ".", template: "0ƒ"},
-	"AZN": {code: "AZN", subunits: 2, thouSep: ",", subSep: ".", template: "m0"},
-	"BAM": {code: "BAM", subunits: 2, thouSep: ",", subSep: ".", template: "KM0"},
-	"BBD": {code: "BBD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"BDT": {code: "BDT", subunits: 2, thouSep: ",", subSep: ".", template: "৳0"},
-	"BGN": {code: "BGN", subunits: 2, thouSep: ",", subSep: ".", template: "лв0"},
-	"BHD": {code: "BHD", subunits: 3, thouSep: ",", subSep: ".", template: "0 .د.ب "},
-	"BIF": {code: "BIF", subunits: 0, thouSep: ",", subSep: ".", template: "0Fr"},
-	"BMD": {code: "BMD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"BND": {code: "BND", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"BOB": {code: "BOB", subunits: 2, thouSep: ",", subSep: ".", template: "Bs.0"},
-	"BRL": {code: "BRL", subunits: 2, thouSep: ".", subSep: ",", template: "R$0"},
-	"BSD": {code: "BSD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"BTN": {code: "BTN", subunits: 2, thouSep: ",", subSep: ".", template: "0Nu."},
-	"BWP": {code: "BWP", subunits: 2, thouSep: ",", subSep: ".", template: "P0"},
-	"BYN": {code: "BYN", subunits: 2, thouSep: " ", subSep: ",", template: "0 p."},
-	"BYR": {code: "BYR", subunits: 0, thouSep: " ", subSep: ",", template: "0 p."},
-	"BZD": {code: "BZD", subunits: 2, thouSep: ",", subSep: ".", template: "BZ$0"},
-	"CAD": {code: "CAD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"CDF": {code: "CDF", subunits: 2, thouSep: ",", subSep: ".", template: "0FC"},
-	"CHF": {code: "CHF", subunits: 2, thouSep: ",", subSep: ".", template: "0 CHF"},
-	"CLF": {code: "CLF", subunits: 4, thouSep: ".", subSep: ",", template: "UF0"},
-	"CLP": {code: "CLP", subunits: 0, thouSep: ".", subSep: ",", template: "$0"},
-	"CNY": {code: "CNY", subunits: 2, thouSep: ",", subSep: ".", template: "0 ¥"},
-	"COP": {code: "COP", subunits: 2, thouSep: ".", subSep: ",", template: "$0"},
-	"CRC": {code: "CRC", subunits: 2, thouSep: ",", subSep: ".", template: "₡0"},
-	"CUC": {code: "CUC", subunits: 2, thouSep: ",", subSep: ".", template: "0$"},
-	"CUP": {code: "CUP", subunits: 2, thouSep: ",", subSep: ".", template: "$MN0"},
-	"CVE": {code: "CVE", subunits: 2, thouSep: ",", subSep: ".", template: "0$"},
-	"CZK": {code: "CZK", subunits: 2, thouSep: ",", subSep: ".", template: "0 Kč"},
-	"DJF": {code: "DJF", subunits: 0, thouSep: ",", subSep: ".", template: "0 Fdj"},
-	"DKK": {code: "DKK", subunits: 2, thouSep: ".", subSep: ",", template: "kr 1"},
-	"DOP": {code: "DOP", subunits: 2, thouSep: ",", subSep: ".", template: "RD$0"},
-	"DZD": {code: "DZD", subunits: 2, thouSep: ",", subSep: ".", template: "0 دج "},
-	"EEK": {code: "EEK", subunits: 2, thouSep: ",", subSep: ".", template: "kr0"},
-	"EGP": {code: "EGP", subunits: 2, thouSep: ",", subSep: ".", template: "ج.م 0"},
-	"ERN": {code: "ERN", subunits: 2, thouSep: ",", subSep: ".", template: "0 Nfk"},
-	"ETB": {code: "ETB", subunits: 2, thouSep: ",", subSep: ".", template: "0 Br"},
-	"EUR": {code: "EUR", subunits: 2, thouSep: ",", subSep: ".", template: "€0"},
-	"FJD": {code: "FJD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"FKP": {code: "FKP", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"GBP": {code: "GBP", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"GEL": {code: "GEL", subunits: 2, thouSep: ",", subSep: ".", template: "0 ლ"},
-	"GGP": {code: "GGP", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"GHC": {code: "GHC", subunits: 2, thouSep: ",", subSep: ".", template: "GH₵0"},
-	"GHS": {code: "GHS", subunits: 2, thouSep: ",", subSep: ".", template: "GH₵0"},
-	"GIP": {code: "GIP", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"GMD": {code: "GMD", subunits: 2, thouSep: ",", subSep: ".", template: "0 D"},
-	"GNF": {code: "GNF", subunits: 0, thouSep: ",", subSep: ".", template: "0 FG"},
-	"GTQ": {code: "GTQ", subunits: 2, thouSep: ",", subSep: ".", template: "Q0"},
-	"GYD": {code: "GYD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"HKD": {code: "HKD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"HNL": {code: "HNL", subunits: 2, thouSep: ",", subSep: ".", template: "L0"},
-	"HRK": {code: "HRK", subunits: 2, thouSep: ".", subSep: ",", template: "0 Kn"},
-	"HTG": {code: "HTG", subunits: 2, thouSep: ".", subSep: ",", template: "0 G"},
-	"HUF": {code: "HUF", subunits: 0, thouSep: ",", subSep: ".", template: "Ft0"},
-	"IDR": {code: "IDR", subunits: 2, thouSep: ",", subSep: ".", template: "Rp0"},
-	"ILS": {code: "ILS", subunits: 2, thouSep: ",", subSep: ".", template: "₪0"},
-	"IMP": {code: "IMP", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"INR": {code: "INR", subunits: 2, thouSep: ",", subSep: ".", template: "₹0"},
-	"IQD": {code: "IQD", subunits: 3, thouSep: ",", subSep: ".", template: "0 د.ع"},
-	"IRR": {code: "IRR", subunits: 2, thouSep: ",", subSep: ".", template: "0 ﷼"},
-	"ISK": {code: "ISK", subunits: 0, thouSep: ".", subSep: ",", template: "Kr0"},
-	"JEP": {code: "JEP", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"JMD": {code: "JMD", subunits: 2, thouSep: ",", subSep: ".", template: "J$0"},
-	"JOD": {code: "JOD", subunits: 3, thouSep: ",", subSep: ".", template: "0 د.أ"},
-	"JPY": {code: "JPY", subunits: 0, thouSep: ",", subSep: ".", template: "¥0"},
-	"KES": {code: "KES", subunits: 2, thouSep: ",", subSep: ".", template: "KSh0"},
-	"KGS": {code: "KGS", subunits: 2, thouSep: ",", subSep: ".", template: "С̲0"},
-	"KHR": {code: "KHR", subunits: 2, thouSep: ",", subSep: ".", template: "៛0"},
-	"KMF": {code: "KMF", subunits: 0, thouSep: ",", subSep: ".", template: "CF0"},
-	"KPW": {code: "KPW", subunits: 0, thouSep: ",", subSep: ".", template: "₩0"},
-	"KRW": {code: "KRW", subunits: 0, thouSep: ",", subSep: ".", template: "₩0"},
-	"KWD": {code: "KWD", subunits: 3, thouSep: ",", subSep: ".", template: "0 د.ك"},
-	"KYD": {code: "KYD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"KZT": {code: "KZT", subunits: 2, thouSep: ",", subSep: ".", template: "₸0"},
-	"LAK": {code: "LAK", subunits: 2, thouSep: ",", subSep: ".", template: "₭0"},
-	"LBP": {code: "LBP", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"LKR": {code: "LKR", subunits: 2, thouSep: ",", subSep: ".", template: "රු, ரூ0"},
-	"LRD": {code: "LRD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"LSL": {code: "LSL", subunits: 2, thouSep: ",", subSep: ".", template: "L0"},
-	"LTL": {code: "LTL", subunits: 2, thouSep: ",", subSep: ".", template: "Lt0"},
-	"LVL": {code: "LVL", subunits: 2, thouSep: ",", subSep: ".", template: "0 Ls"},
-	"LYD": {code: "LYD", subunits: 3, thouSep: ",", subSep: ".", template: "0 ل.د"},
-	"MAD": {code: "MAD", subunits: 2, thouSep: ",", subSep: ".", template: "0 DH"},
-	"MDL": {code: "MDL", subunits: 2, thouSep: ",", subSep: ".", template: "0 lei"},
-	"MKD": {code: "MKD", subunits: 2, thouSep: ",", subSep: ".", template: "ден0"},
-	"MMK": {code: "MMK", subunits: 2, thouSep: ",", subSep: ".", template: "K0"},
-	"MNT": {code: "MNT", subunits: 2, thouSep: ",", subSep: ".", template: "₮0"},
-	"MOP": {code: "MOP", subunits: 2, thouSep: ",", subSep: ".", template: "0 P"},
-	"MUR": {code: "MUR", subunits: 2, thouSep: ",", subSep: ".", template: "₨0"},
-	"MVR": {code: "MVR", subunits: 2, thouSep: ",", subSep: ".", template: "0 MVR"},
-	"MWK": {code: "MWK", subunits: 2, thouSep: ",", subSep: ".", template: "MK0"},
-	"MXN": {code: "MXN", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"MYR": {code: "MYR", subunits: 2, thouSep: ",", subSep: ".", template: "RM0"},
-	"MZN": {code: "MZN", subunits: 2, thouSep: ",", subSep: ".", template: "MT0"},
-	"NAD": {code: "NAD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"NGN": {code: "NGN", subunits: 2, thouSep: ",", subSep: ".", template: "₦0"},
-	"NIO": {code: "NIO", subunits: 2, thouSep: ",", subSep: ".", template: "C$0"},
-	"NOK": {code: "NOK", subunits: 2, thouSep: ",", subSep: ".", template: "0 Kr"},
-	"NPR": {code: "NPR", subunits: 2, thouSep: ",", subSep: ".", template: "रु0"},
-	"NZD": {code: "NZD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"OMR": {code: "OMR", subunits: 3, thouSep: ",", subSep: ".", template: "0 ر.ع."},
-	"PAB": {code: "PAB", subunits: 2, thouSep: ",", subSep: ".", template: "B/.0"},
-	"PEN": {code: "PEN", subunits: 2, thouSep: ",", subSep: ".", template: "S/0"},
-	"PGK": {code: "PGK", subunits: 2, thouSep: ",", subSep: ".", template: "0 K"},
-	"PHP": {code: "PHP", subunits: 2, thouSep: ",", subSep: ".", template: "₱0"},
-	"PKR": {code: "PKR", subunits: 2, thouSep: ",", subSep: ".", template: "₨0"},
-	"PLN": {code: "PLN", subunits: 2, thouSep: ",", subSep: ".", template: "0 zł"},
-	"PYG": {code: "PYG", subunits: 0, thouSep: ",", subSep: ".", template: "0Gs"},
-	"QAR": {code: "QAR", subunits: 2, thouSep: ",", subSep: ".", template: "0 ر.ق"},
-	"RON": {code: "RON", subunits: 2, thouSep: ",", subSep: ".", template: "lei0"},
-	"RSD": {code: "RSD", subunits: 2, thouSep: ",", subSep: ".", template: "дин0"},
-	"RUB": {code: "RUB", subunits: 2, thouSep: ",", subSep: ".", template: "0 ₽"},
-	"RUR": {code: "RUR", subunits: 2, thouSep: ",", subSep: ".", template: "0 ₽"},
-	"RWF": {code: "RWF", subunits: 0, thouSep: ",", subSep: ".", template: "0 FRw"},
-	"SAR": {code: "SAR", subunits: 2, thouSep: ",", subSep: ".", template: "0 ر.س"},
-	"SBD": {code: "SBD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"SCR": {code: "SCR", subunits: 2, thouSep: ",", subSep: ".", template: "SCR0"},
-	"SDG": {code: "SDG", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"SEK": {code: "SEK", subunits: 2, thouSep: ",", subSep: ".", template: "0 Kr"},
-	"SGD": {code: "SGD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"SHP": {code: "SHP", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"SKK": {code: "SKK", subunits: 2, thouSep: ",", subSep: ".", template: "Sk0"},
-	"SLL": {code: "SLL", subunits: 2, thouSep: ",", subSep: ".", template: "0 Le"},
-	"SOS": {code: "SOS", subunits: 2, thouSep: ",", subSep: ".", template: "0 Sh"},
-	"SRD": {code: "SRD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"SSP": {code: "SSP", subunits: 2, thouSep: ",", subSep: ".", template: "0 £"},
-	"STD": {code: "STD", subunits: 2, thouSep: ",", subSep: ".", template: "0 Db"},
-	"SVC": {code: "SVC", subunits: 2, thouSep: ",", subSep: ".", template: "₡0"},
-	"SYP": {code: "SYP", subunits: 2, thouSep: ",", subSep: ".", template: "0 £"},
-	"SZL": {code: "SZL", subunits: 2, thouSep: ",", subSep: ".", template: "£0"},
-	"THB": {code: "THB", subunits: 2, thouSep: ",", subSep: ".", template: "฿ 20"},
-	"TJS": {code: "TJS", subunits: 2, thouSep: ",", subSep: ".", template: "0 SM"},
-	"TMT": {code: "TMT", subunits: 2, thouSep: ",", subSep: ".", template: "0 T"},
-	"TND": {code: "TND", subunits: 3, thouSep: ",", subSep: ".", template: "0 د.ت"},
-	"TOP": {code: "TOP", subunits: 2, thouSep: ",", subSep: ".", template: "T$0"},
-	"TRL": {code: "TRL", subunits: 2, thouSep: ",", subSep: ".", template: "₺0"},
-	"TRY": {code: "TRY", subunits: 2, thouSep: ",", subSep: ".", template: "₺0"},
-	"TTD": {code: "TTD", subunits: 2, thouSep: ",", subSep: ".", template: "TT$0"},
-	"TWD": {code: "TWD", subunits: 2, thouSep: ",", subSep: ".", template: "NT$0"},
-	"TZS": {code: "TZS", subunits: 0, thouSep: ",", subSep: ".", template: "TSh0"},
-	"UAH": {code: "UAH", subunits: 2, thouSep: ",", subSep: ".", template: "0 ₴"},
-	"UGX": {code: "UGX", subunits: 0, thouSep: ",", subSep: ".", template: "0 USh"},
-	"USD": {code: "USD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"UYU": {code: "UYU", subunits: 2, thouSep: ",", subSep: ".", template: "U$0"},
-	"UZS": {code: "UZS", subunits: 2, thouSep: ",", subSep: ".", template: "сум0"},
-	"VEF": {code: "VEF", subunits: 2, thouSep: ",", subSep: ".", template: "Bs0"},
-	"VND": {code: "VND", subunits: 0, thouSep: ",", subSep: ".", template: "0 ₫"},
-	"VUV": {code: "VUV", subunits: 0, thouSep: ",", subSep: ".", template: "Vt0"},
-	"WST": {code: "WST", subunits: 2, thouSep: ",", subSep: ".", template: "0 T"},
-	"XAF": {code: "XAF", subunits: 0, thouSep: ",", subSep: ".", template: "0 Fr"},
-	"XAG": {code: "XAG", subunits: 0, thouSep: ",", subSep: ".", template: "0 oz t"},
-	"XAU": {code: "XAU", subunits: 0, thouSep: ",", subSep: ".", template: "0 oz t"},
-	"XCD": {code: "XCD", subunits: 2, thouSep: ",", subSep: ".", template: "$0"},
-	"XDR": {code: "XDR", subunits: 0, thouSep: ",", subSep: ".", template: "0 SDR"},
-	"XPF": {code: "XPF", subunits: 0, thouSep: ",", subSep: ".", template: "0 ₣"},
-	"YER": {code: "YER", subunits: 2, thouSep: ",", subSep: ".", template: "0 ر.ي, ﷼"},
-	"ZAR": {code: "ZAR", subunits: 2, thouSep: ",", subSep: ".", template: "R0"},
-	"ZMW": {code: "ZMW", subunits: 2, thouSep: ",", subSep: ".", template: "ZK0"},
-	"ZWD": {code: "ZWD", subunits: 2, thouSep: ",", subSep: ".", template: "Z$0"},
+// builtinCurrencies contain a map of all currencies registered by default.
+var builtinCurrencies = map[string]Currency{
+	"AED": {Code: "AED", Numeric: 784, Name: "UAE Dirham", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 د.إ"},
+	"AFN": {Code: "AFN", Numeric: 971, Name: "Afghani", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ؋"},
+	"ALL": {Code: "ALL", Numeric: 8, Name: "Lek", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "L0"},
+	"AMD": {Code: "AMD", Numeric: 51, Name: "Armenian Dram", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ֏"},
+	"ANG": {Code: "ANG", Numeric: 532, Name: "Netherlands Antillean Guilder", Exponent: 2, Subunits: 2, ThouSep: ".", SubSep: ",", Template: "ƒ0"},
+	"AOA": {Code: "AOA", Numeric: 973, Name: "Kwanza", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0Kz"},
+	"ARS": {Code: "ARS", Numeric: 32, Name: "Argentine Peso", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"AUD": {Code: "AUD", Numeric: 36, Name: "Australian Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"AWG": {Code: "AWG", Numeric: 533, Name: "Aruban Florin", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0ƒ"},
+	"AZN": {Code: "AZN", Numeric: 944, Name: "Azerbaijan Manat", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "m0"},
+	"BAM": {Code: "BAM", Numeric: 977, Name: "Convertible Mark", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "KM0"},
+	"BBD": {Code: "BBD", Numeric: 52, Name: "Barbados Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"BDT": {Code: "BDT", Numeric: 50, Name: "Taka", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "৳0"},
+	"BGN": {Code: "BGN", Numeric: 975, Name: "Bulgarian Lev", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "лв0", Withdrawn: true},
+	"BHD": {Code: "BHD", Numeric: 48, Name: "Bahraini Dinar", Exponent: 3, Subunits: 3, ThouSep: ",", SubSep: ".", Template: "0 .د.ب "},
+	"BIF": {Code: "BIF", Numeric: 108, Name: "Burundi Franc", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0Fr"},
+	"BMD": {Code: "BMD", Numeric: 60, Name: "Bermudian Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"BND": {Code: "BND", Numeric: 96, Name: "Brunei Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"BOB": {Code: "BOB", Numeric: 68, Name: "Boliviano", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "Bs.0"},
+	"BRL": {Code: "BRL", Numeric: 986, Name: "Brazilian Real", Exponent: 2, Subunits: 2, ThouSep: ".", SubSep: ",", Template: "R$0"},
+	"BSD": {Code: "BSD", Numeric: 44, Name: "Bahamian Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"BTN": {Code: "BTN", Numeric: 64, Name: "Ngultrum", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0Nu."},
+	"BWP": {Code: "BWP", Numeric: 72, Name: "Pula", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "P0"},
+	"BYN": {Code: "BYN", Numeric: 933, Name: "Belarusian Ruble", Exponent: 2, Subunits: 2, ThouSep: " ", SubSep: ",", Template: "0 p."},
+	"BYR": {Code: "BYR", Numeric: 974, Name: "Belarusian Ruble", Exponent: 0, Subunits: 0, ThouSep: " ", SubSep: ",", Template: "0 p.", Withdrawn: true},
+	"BZD": {Code: "BZD", Numeric: 84, Name: "Belize Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "BZ$0"},
+	"CAD": {Code: "CAD", Numeric: 124, Name: "Canadian Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"CDF": {Code: "CDF", Numeric: 976, Name: "Congolese Franc", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0FC"},
+	"CHF": {Code: "CHF", Numeric: 756, Name: "Swiss Franc", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 CHF"},
+	"CLF": {Code: "CLF", Numeric: 990, Name: "Unidad de Fomento", Exponent: 4, Subunits: 4, ThouSep: ".", SubSep: ",", Template: "UF0"},
+	"CLP": {Code: "CLP", Numeric: 152, Name: "Chilean Peso", Exponent: 0, Subunits: 0, ThouSep: ".", SubSep: ",", Template: "$0"},
+	"CNY": {Code: "CNY", Numeric: 156, Name: "Yuan Renminbi", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ¥"},
+	"COP": {Code: "COP", Numeric: 170, Name: "Colombian Peso", Exponent: 2, Subunits: 2, ThouSep: ".", SubSep: ",", Template: "$0"},
+	"CRC": {Code: "CRC", Numeric: 188, Name: "Costa Rican Colon", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₡0"},
+	"CUC": {Code: "CUC", Numeric: 931, Name: "Peso Convertible", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0$"},
+	"CUP": {Code: "CUP", Numeric: 192, Name: "Cuban Peso", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$MN0"},
+	"CVE": {Code: "CVE", Numeric: 132, Name: "Cabo Verde Escudo", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0$"},
+	"CZK": {Code: "CZK", Numeric: 203, Name: "Czech Koruna", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Kč"},
+	"DJF": {Code: "DJF", Numeric: 262, Name: "Djibouti Franc", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 Fdj"},
+	"DKK": {Code: "DKK", Numeric: 208, Name: "Danish Krone", Exponent: 2, Subunits: 2, ThouSep: ".", SubSep: ",", Template: "kr 0"},
+	"DOP": {Code: "DOP", Numeric: 214, Name: "Dominican Peso", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "RD$0"},
+	"DZD": {Code: "DZD", Numeric: 12, Name: "Algerian Dinar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 دج "},
+	"EEK": {Code: "EEK", Numeric: 233, Name: "Kroon", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "kr0", Withdrawn: true},
+	"EGP": {Code: "EGP", Numeric: 818, Name: "Egyptian Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "ج.م 0"},
+	"ERN": {Code: "ERN", Numeric: 232, Name: "Nakfa", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Nfk"},
+	"ETB": {Code: "ETB", Numeric: 230, Name: "Ethiopian Birr", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Br"},
+	"EUR": {Code: "EUR", Numeric: 978, Name: "Euro", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "€0"},
+	"FJD": {Code: "FJD", Numeric: 242, Name: "Fiji Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"FKP": {Code: "FKP", Numeric: 238, Name: "Falkland Islands Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"GBP": {Code: "GBP", Numeric: 826, Name: "Pound Sterling", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"GEL": {Code: "GEL", Numeric: 981, Name: "Lari", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ლ"},
+	"GGP": {Code: "GGP", Numeric: 0, Name: "Guernsey Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"GHC": {Code: "GHC", Numeric: 288, Name: "Cedi", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "GH₵0", Withdrawn: true},
+	"GHS": {Code: "GHS", Numeric: 936, Name: "Ghana Cedi", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "GH₵0"},
+	"GIP": {Code: "GIP", Numeric: 292, Name: "Gibraltar Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"GMD": {Code: "GMD", Numeric: 270, Name: "Dalasi", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 D"},
+	"GNF": {Code: "GNF", Numeric: 324, Name: "Guinean Franc", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 FG"},
+	"GTQ": {Code: "GTQ", Numeric: 320, Name: "Quetzal", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "Q0"},
+	"GYD": {Code: "GYD", Numeric: 328, Name: "Guyana Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"HKD": {Code: "HKD", Numeric: 344, Name: "Hong Kong Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"HNL": {Code: "HNL", Numeric: 340, Name: "Lempira", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "L0"},
+	"HRK": {Code: "HRK", Numeric: 191, Name: "Kuna", Exponent: 2, Subunits: 2, ThouSep: ".", SubSep: ",", Template: "0 Kn", Withdrawn: true},
+	"HTG": {Code: "HTG", Numeric: 332, Name: "Gourde", Exponent: 2, Subunits: 2, ThouSep: ".", SubSep: ",", Template: "0 G"},
+	"HUF": {Code: "HUF", Numeric: 348, Name: "Forint", Exponent: 2, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "Ft0"},
+	"IDR": {Code: "IDR", Numeric: 360, Name: "Rupiah", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "Rp0"},
+	"ILS": {Code: "ILS", Numeric: 376, Name: "New Israeli Sheqel", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₪0"},
+	"IMP": {Code: "IMP", Numeric: 0, Name: "Manx Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"INR": {Code: "INR", Numeric: 356, Name: "Indian Rupee", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₹0"},
+	"IQD": {Code: "IQD", Numeric: 368, Name: "Iraqi Dinar", Exponent: 3, Subunits: 3, ThouSep: ",", SubSep: ".", Template: "0 د.ع"},
+	"IRR": {Code: "IRR", Numeric: 364, Name: "Iranian Rial", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ﷼"},
+	"ISK": {Code: "ISK", Numeric: 352, Name: "Iceland Krona", Exponent: 0, Subunits: 0, ThouSep: ".", SubSep: ",", Template: "Kr0"},
+	"JEP": {Code: "JEP", Numeric: 0, Name: "Jersey Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"JMD": {Code: "JMD", Numeric: 388, Name: "Jamaican Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "J$0"},
+	"JOD": {Code: "JOD", Numeric: 400, Name: "Jordanian Dinar", Exponent: 3, Subunits: 3, ThouSep: ",", SubSep: ".", Template: "0 د.أ"},
+	"JPY": {Code: "JPY", Numeric: 392, Name: "Yen", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "¥0"},
+	"KES": {Code: "KES", Numeric: 404, Name: "Kenyan Shilling", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "KSh0"},
+	"KGS": {Code: "KGS", Numeric: 417, Name: "Som", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "С̲0"},
+	"KHR": {Code: "KHR", Numeric: 116, Name: "Riel", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "៛0"},
+	"KMF": {Code: "KMF", Numeric: 174, Name: "Comorian Franc", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "CF0"},
+	"KPW": {Code: "KPW", Numeric: 408, Name: "North Korean Won", Exponent: 2, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "₩0"},
+	"KRW": {Code: "KRW", Numeric: 410, Name: "Won", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "₩0"},
+	"KWD": {Code: "KWD", Numeric: 414, Name: "Kuwaiti Dinar", Exponent: 3, Subunits: 3, ThouSep: ",", SubSep: ".", Template: "0 د.ك"},
+	"KYD": {Code: "KYD", Numeric: 136, Name: "Cayman Islands Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"KZT": {Code: "KZT", Numeric: 398, Name: "Tenge", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₸0"},
+	"LAK": {Code: "LAK", Numeric: 418, Name: "Lao Kip", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₭0"},
+	"LBP": {Code: "LBP", Numeric: 422, Name: "Lebanese Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"LKR": {Code: "LKR", Numeric: 144, Name: "Sri Lanka Rupee", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "රු, ரூ0"},
+	"LRD": {Code: "LRD", Numeric: 430, Name: "Liberian Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"LSL": {Code: "LSL", Numeric: 426, Name: "Loti", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "L0"},
+	"LTL": {Code: "LTL", Numeric: 440, Name: "Lithuanian Litas", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "Lt0", Withdrawn: true},
+	"LVL": {Code: "LVL", Numeric: 428, Name: "Latvian Lats", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Ls", Withdrawn: true},
+	"LYD": {Code: "LYD", Numeric: 434, Name: "Libyan Dinar", Exponent: 3, Subunits: 3, ThouSep: ",", SubSep: ".", Template: "0 ل.د"},
+	"MAD": {Code: "MAD", Numeric: 504, Name: "Moroccan Dirham", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 DH"},
+	"MDL": {Code: "MDL", Numeric: 498, Name: "Moldovan Leu", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 lei"},
+	"MKD": {Code: "MKD", Numeric: 807, Name: "Denar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "ден0"},
+	"MMK": {Code: "MMK", Numeric: 104, Name: "Kyat", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "K0"},
+	"MNT": {Code: "MNT", Numeric: 496, Name: "Tugrik", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₮0"},
+	"MOP": {Code: "MOP", Numeric: 446, Name: "Pataca", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 P"},
+	"MUR": {Code: "MUR", Numeric: 480, Name: "Mauritius Rupee", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₨0"},
+	"MVR": {Code: "MVR", Numeric: 462, Name: "Rufiyaa", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 MVR"},
+	"MWK": {Code: "MWK", Numeric: 454, Name: "Malawi Kwacha", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "MK0"},
+	"MXN": {Code: "MXN", Numeric: 484, Name: "Mexican Peso", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"MYR": {Code: "MYR", Numeric: 458, Name: "Malaysian Ringgit", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "RM0"},
+	"MZN": {Code: "MZN", Numeric: 943, Name: "Mozambique Metical", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "MT0"},
+	"NAD": {Code: "NAD", Numeric: 516, Name: "Namibia Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"NGN": {Code: "NGN", Numeric: 566, Name: "Naira", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₦0"},
+	"NIO": {Code: "NIO", Numeric: 558, Name: "Cordoba Oro", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "C$0"},
+	"NOK": {Code: "NOK", Numeric: 578, Name: "Norwegian Krone", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Kr"},
+	"NPR": {Code: "NPR", Numeric: 524, Name: "Nepalese Rupee", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "रु0"},
+	"NZD": {Code: "NZD", Numeric: 554, Name: "New Zealand Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"OMR": {Code: "OMR", Numeric: 512, Name: "Rial Omani", Exponent: 3, Subunits: 3, ThouSep: ",", SubSep: ".", Template: "0 ر.ع."},
+	"PAB": {Code: "PAB", Numeric: 590, Name: "Balboa", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "B/.0"},
+	"PEN": {Code: "PEN", Numeric: 604, Name: "Sol", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "S/0"},
+	"PGK": {Code: "PGK", Numeric: 598, Name: "Kina", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 K"},
+	"PHP": {Code: "PHP", Numeric: 608, Name: "Philippine Peso", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₱0"},
+	"PKR": {Code: "PKR", Numeric: 586, Name: "Pakistan Rupee", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₨0"},
+	"PLN": {Code: "PLN", Numeric: 985, Name: "Zloty", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 zł"},
+	"PYG": {Code: "PYG", Numeric: 600, Name: "Guarani", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0Gs"},
+	"QAR": {Code: "QAR", Numeric: 634, Name: "Qatari Rial", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ر.ق"},
+	"RON": {Code: "RON", Numeric: 946, Name: "Romanian Leu", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "lei0"},
+	"RSD": {Code: "RSD", Numeric: 941, Name: "Serbian Dinar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "дин0"},
+	"RUB": {Code: "RUB", Numeric: 643, Name: "Russian Ruble", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ₽"},
+	"RUR": {Code: "RUR", Numeric: 810, Name: "Russian Ruble", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ₽", Withdrawn: true},
+	"RWF": {Code: "RWF", Numeric: 646, Name: "Rwanda Franc", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 FRw"},
+	"SAR": {Code: "SAR", Numeric: 682, Name: "Saudi Riyal", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ر.س"},
+	"SBD": {Code: "SBD", Numeric: 90, Name: "Solomon Islands Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"SCR": {Code: "SCR", Numeric: 690, Name: "Seychelles Rupee", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "SCR0"},
+	"SDG": {Code: "SDG", Numeric: 938, Name: "Sudanese Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"SEK": {Code: "SEK", Numeric: 752, Name: "Swedish Krona", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Kr"},
+	"SGD": {Code: "SGD", Numeric: 702, Name: "Singapore Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"SHP": {Code: "SHP", Numeric: 654, Name: "Saint Helena Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"SKK": {Code: "SKK", Numeric: 703, Name: "Slovak Koruna", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "Sk0", Withdrawn: true},
+	"SLL": {Code: "SLL", Numeric: 694, Name: "Leone", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Le"},
+	"SOS": {Code: "SOS", Numeric: 706, Name: "Somali Shilling", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Sh"},
+	"SRD": {Code: "SRD", Numeric: 968, Name: "Surinam Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"SSP": {Code: "SSP", Numeric: 728, Name: "South Sudanese Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 £"},
+	"STD": {Code: "STD", Numeric: 678, Name: "Dobra", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 Db", Withdrawn: true},
+	"SVC": {Code: "SVC", Numeric: 222, Name: "El Salvador Colon", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₡0"},
+	"SYP": {Code: "SYP", Numeric: 760, Name: "Syrian Pound", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 £"},
+	"SZL": {Code: "SZL", Numeric: 748, Name: "Lilangeni", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "£0"},
+	"THB": {Code: "THB", Numeric: 764, Name: "Baht", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "฿0"},
+	"TJS": {Code: "TJS", Numeric: 972, Name: "Somoni", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 SM"},
+	"TMT": {Code: "TMT", Numeric: 934, Name: "Turkmenistan New Manat", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 T"},
+	"TND": {Code: "TND", Numeric: 788, Name: "Tunisian Dinar", Exponent: 3, Subunits: 3, ThouSep: ",", SubSep: ".", Template: "0 د.ت"},
+	"TOP": {Code: "TOP", Numeric: 776, Name: "Pa'anga", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "T$0"},
+	"TRL": {Code: "TRL", Numeric: 792, Name: "Turkish Lira", Exponent: 0, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₺0", Withdrawn: true},
+	"TRY": {Code: "TRY", Numeric: 949, Name: "Turkish Lira", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "₺0"},
+	"TTD": {Code: "TTD", Numeric: 780, Name: "Trinidad and Tobago Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "TT$0"},
+	"TWD": {Code: "TWD", Numeric: 901, Name: "New Taiwan Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "NT$0"},
+	"TZS": {Code: "TZS", Numeric: 834, Name: "Tanzanian Shilling", Exponent: 2, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "TSh0"},
+	"UAH": {Code: "UAH", Numeric: 980, Name: "Hryvnia", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ₴"},
+	"UGX": {Code: "UGX", Numeric: 800, Name: "Uganda Shilling", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 USh"},
+	"USD": {Code: "USD", Numeric: 840, Name: "US Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"UYU": {Code: "UYU", Numeric: 858, Name: "Peso Uruguayo", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "U$0"},
+	"UZS": {Code: "UZS", Numeric: 860, Name: "Uzbekistan Sum", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "сум0"},
+	"VEF": {Code: "VEF", Numeric: 937, Name: "Bolívar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "Bs0", Withdrawn: true},
+	"VND": {Code: "VND", Numeric: 704, Name: "Dong", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 ₫"},
+	"VUV": {Code: "VUV", Numeric: 548, Name: "Vatu", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "Vt0"},
+	"WST": {Code: "WST", Numeric: 882, Name: "Tala", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 T"},
+	"XAF": {Code: "XAF", Numeric: 950, Name: "CFA Franc BEAC", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 Fr"},
+	"XAG": {Code: "XAG", Numeric: 961, Name: "Silver", Exponent: -1, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 oz t"},
+	"XAU": {Code: "XAU", Numeric: 959, Name: "Gold", Exponent: -1, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 oz t"},
+	"XCD": {Code: "XCD", Numeric: 951, Name: "East Caribbean Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "$0"},
+	"XDR": {Code: "XDR", Numeric: 960, Name: "SDR (Special Drawing Right)", Exponent: -1, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 SDR"},
+	"XPF": {Code: "XPF", Numeric: 953, Name: "CFP Franc", Exponent: 0, Subunits: 0, ThouSep: ",", SubSep: ".", Template: "0 ₣"},
+	"YER": {Code: "YER", Numeric: 886, Name: "Yemeni Rial", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "0 ر.ي, ﷼"},
+	"ZAR": {Code: "ZAR", Numeric: 710, Name: "Rand", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "R0"},
+	"ZMW": {Code: "ZMW", Numeric: 967, Name: "Zambian Kwacha", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "ZK0"},
+	"ZWD": {Code: "ZWD", Numeric: 716, Name: "Zimbabwe Dollar", Exponent: 2, Subunits: 2, ThouSep: ",", SubSep: ".", Template: "Z$0", Withdrawn: true},
+}
+
+// builtinAliases contain a map of currency aliases registered by default. The
+// pound sign is shared by several currencies pegged to sterling, so it's
+// registered as an alias to make it unambiguous.
+var builtinAliases = map[string]string{
+	"£":   "GBP",
+	"A$":  "AUD",
+	"AU$": "AUD",
+	"C$":  "CAD",
+	"CA$": "CAD",
+	"HK$": "HKD",
+	"NIS": "ILS",
+	"NT$": "TWD",
+	"NZ$": "NZD",
+	"RMB": "CNY",
+	"S$":  "SGD",
+	"SFR": "CHF",
+	"US$": "USD",
 }
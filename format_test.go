@@ -193,3 +193,107 @@ func TestCLFString(t *testing.T) {
 	assertMoneyString(t, m, "CLF", "UF12.345,6789")
 	assertMoneyStringNoSymbol(t, m, "CLF", "12.345,6789")
 }
+
+func TestNegativeString(t *testing.T) {
+	m, _ := MoneyGBP(-5)
+	assertMoneyString(t, m, "GBP", "£-0.05")
+
+	m, _ = MoneyGBP(-55)
+	assertMoneyString(t, m, "GBP", "£-0.55")
+
+	m, _ = MoneyGBP(-123456)
+	assertMoneyString(t, m, "GBP", "£-1,234.56")
+
+	m, _ = MoneyFromSubunits("JPY", -123456, nil)
+	assertMoneyString(t, m, "JPY", "¥-123,456")
+}
+
+func TestFixedTemplateString(t *testing.T) {
+	m, _ := MoneyFromSubunits("DKK", 123456, nil)
+	assertMoneyString(t, m, "DKK", "kr 1.234,56")
+
+	m, _ = MoneyFromSubunits("THB", 123456, nil)
+	assertMoneyString(t, m, "THB", "฿1,234.56")
+}
+
+func TestNegativeStyleString(t *testing.T) {
+	tests := []struct {
+		code     string
+		value    int64
+		style    NegativeStyle
+		expected string
+	}{
+		{"GBP", -1055, NegativeSymbolMinus, "£-10.55"},
+		{"GBP", -1055, NegativeLeadingMinus, "-£10.55"},
+		{"GBP", -1055, NegativeParentheses, "(£10.55)"},
+		{"GBP", -1055, NegativeTrailingMinus, "£10.55-"},
+		{"GBP", -1055, NegativeCRDR, "£10.55 CR"},
+		{"GBP", 1055, NegativeCRDR, "£10.55 DR"},
+		{"GBP", 0, NegativeCRDR, "£0.00"},
+		{"GBP", 1055, NegativeParentheses, "£10.55"},
+		{"CHF", -1055, NegativeSymbolMinus, "-10.55 CHF"},
+		{"CHF", -1055, NegativeLeadingMinus, "-10.55 CHF"},
+		{"CHF", -1055, NegativeParentheses, "(10.55 CHF)"},
+		{"CHF", -1055, NegativeTrailingMinus, "10.55- CHF"},
+		{"CHF", -1055, NegativeCRDR, "10.55 CHF CR"},
+	}
+	for _, test := range tests {
+		m, _ := MoneyFromSubunits(test.code, test.value, nil)
+		if str := m.StringStyle(test.style); str != test.expected {
+			t.Errorf("StringStyle(%d) returned '%s', expected '%s'", test.style, str, test.expected)
+		}
+
+		parsed, err := MoneyFromString(test.code, test.expected, nil)
+		if err != nil {
+			t.Errorf("MoneyFromString(%s) failed: %s", test.expected, err)
+		}
+		assertMoneyValue(t, parsed, test.value)
+	}
+}
+
+func TestCurrencyNegativeStyle(t *testing.T) {
+	defer RegisterCurrency(builtinCurrencies["GBP"])
+
+	c := builtinCurrencies["GBP"]
+	c.Negative = NegativeParentheses
+	if err := RegisterCurrency(c); err != nil {
+		t.Errorf("RegisterCurrency failed: %s", err)
+	}
+
+	m, _ := MoneyGBP(-1055)
+	assertMoneyString(t, m, "GBP", "(£10.55)")
+	assert(t, m.StringStyle(NegativeLeadingMinus) == "-£10.55")
+	assert(t, m.StringNoSymbol() == "-10.55")
+
+	p, _ := PriceFromSubunits("GBP", -1055, nil)
+	assert(t, p.String() == "(£10.55)")
+	assert(t, p.StringStyle(NegativeTrailingMinus) == "£10.55-")
+
+	c.Negative = NegativeCRDR + 1
+	if err := RegisterCurrency(c); err == nil {
+		t.Errorf("RegisterCurrency failed to error on an invalid negative style")
+	}
+}
+
+func TestLocaleNegativeStyle(t *testing.T) {
+	m, _ := MoneyFromSubunits("EUR", -123456, nil)
+
+	l, _ := LookupLocale("de-DE")
+	l.Negative = NegativeParentheses
+	assert(t, l.FormatMoney(m) == "(1.234,56 €)")
+	l.Negative = NegativeTrailingMinus
+	assert(t, l.FormatMoney(m) == "1.234,56- €")
+	l.Negative = NegativeCRDR
+	assert(t, l.FormatMoney(m) == "1.234,56 € CR")
+	assert(t, l.FormatMoney(m.Abs()) == "1.234,56 € DR")
+}
+
+func TestBigMoneyNegativeStyle(t *testing.T) {
+	m, _ := MoneyGBP(-1055)
+	b := m.Big()
+	assert(t, b.String() == "£-10.55")
+	assert(t, b.StringStyle(NegativeParentheses) == "(£10.55)")
+
+	parsed, _ := BigMoneyFromString("GBP", "(£10.55)", nil)
+	assert(t, parsed.Eq(b))
+}
@@ -0,0 +1,177 @@
+package mongo
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format is an implementation of fmt.Formatter. The following verbs are
+// supported:
+//
+//	%s, %v  the formatted value including the currency symbol, e.g. "£1,234.56"
+//	%+v     the ISO code, plain decimal value and rounding, e.g. "GBP 1234.56 HalfUp"
+//	%q      the formatted value as a double quoted string
+//	%d      the value in subunits, e.g. "123456"
+//	%f      the value as a plain decimal, e.g. "1234.56"
+//	%.2f    the value as a plain decimal rounded to a precision using the money's rounding
+//
+// The width flag pads the value with spaces, the '-' flag aligns it to the
+// left and the '0' flag pads numeric verbs with leading zeros. The '+' flag
+// always prints the sign of numeric verbs.
+func (m Money) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		pad(s, verb, detailString(m.currency, big.NewInt(m.value), m.round))
+		return
+	}
+	formatMoney(s, verb, "mongo.Money", m.String(), m.currency, big.NewInt(m.value), m.round)
+}
+
+// Format is an implementation of fmt.Formatter and supports the same verbs as
+// Money.
+func (b BigMoney) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		pad(s, verb, detailString(b.currency, b.int(), b.round))
+		return
+	}
+	formatMoney(s, verb, "mongo.BigMoney", b.String(), b.currency, b.int(), b.round)
+}
+
+// Format is an implementation of fmt.Formatter and supports the same verbs as
+// Money, using the gross value. The %+v verb also prints the net and tax
+// values, e.g. "GBP 12.00 (net 10.00, tax 2.00) HalfUp".
+func (p Price) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		c := p.gross.currency
+		str := fmt.Sprintf("%s %s (net %s, tax %s)",
+			c.Code,
+			decimalString(c, big.NewInt(p.gross.value), c.Subunits, p.gross.round),
+			decimalString(c, big.NewInt(p.Net().value), c.Subunits, p.gross.round),
+			decimalString(c, big.NewInt(p.taxes.total.value), c.Subunits, p.gross.round),
+		)
+		if name := roundName(p.gross.round); name != "" {
+			str += " " + name
+		}
+		pad(s, verb, str)
+		return
+	}
+	formatMoney(s, verb, "mongo.Price", p.String(), p.gross.currency, big.NewInt(p.gross.value), p.gross.round)
+}
+
+// formatMoney writes a monetary value to the formatter state using the passed
+// verb. typ and str are used to report unsupported verbs in the same way as
+// the fmt package.
+func formatMoney(s fmt.State, verb rune, typ string, str string, c Currency, value *big.Int, f RoundingMode) {
+	switch verb {
+	case 's', 'v':
+	case 'q':
+		str = strconv.Quote(str)
+	case 'd':
+		str = signed(s, value.String())
+	case 'f', 'F':
+		prec, ok := s.Precision()
+		if !ok {
+			prec = c.Subunits
+		}
+		str = signed(s, decimalString(c, value, prec, f))
+	default:
+		fmt.Fprintf(s, "%%!%c(%s=%s)", verb, typ, str)
+		return
+	}
+	pad(s, verb, str)
+}
+
+// pad writes the string to the formatter state padded to the requested width.
+func pad(s fmt.State, verb rune, str string) {
+	width, ok := s.Width()
+	n := utf8.RuneCountInString(str)
+	if !ok || n >= width {
+		fmt.Fprint(s, str)
+		return
+	}
+
+	padding := width - n
+	switch {
+	case s.Flag('-'):
+		str = str + strings.Repeat(" ", padding)
+	case s.Flag('0') && (verb == 'd' || verb == 'f' || verb == 'F'):
+		sign := ""
+		if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+			sign, str = str[:1], str[1:]
+		}
+		str = sign + strings.Repeat("0", padding) + str
+	default:
+		str = strings.Repeat(" ", padding) + str
+	}
+	fmt.Fprint(s, str)
+}
+
+// signed prefixes a plus sign to non-negative numbers if the '+' flag is set.
+func signed(s fmt.State, str string) string {
+	if s.Flag('+') && !strings.HasPrefix(str, "-") {
+		return "+" + str
+	}
+	return str
+}
+
+// detailString returns the ISO code, plain decimal value and rounding function
+// name of a monetary value.
+func detailString(c Currency, value *big.Int, f RoundingMode) string {
+	str := c.Code + " " + decimalString(c, value, c.Subunits, f)
+	if name := roundName(f); name != "" {
+		str += " " + name
+	}
+	return str
+}
+
+// decimalString returns a plain decimal representation of the passed subunits
+// with prec digits after the decimal point. If fewer digits than the currency's
+// subunits are requested the value is rounded using the passed function.
+func decimalString(c Currency, value *big.Int, prec int, f RoundingMode) string {
+	if prec < 0 {
+		prec = 0
+	}
+	if prec < c.Subunits {
+		if f == nil {
+			f = RoundHalfUp
+		}
+		den := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.Subunits-prec)), nil)
+		value = roundRat(new(big.Rat).SetFrac(value, den), f)
+	} else if prec > c.Subunits {
+		mul := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(prec-c.Subunits)), nil)
+		value = new(big.Int).Mul(value, mul)
+	}
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	str := new(big.Int).Abs(value).String()
+	if prec == 0 {
+		return sign + str
+	}
+	if len(str) <= prec {
+		str = strings.Repeat("0", prec-len(str)+1) + str
+	}
+	return sign + str[:len(str)-prec] + "." + str[len(str)-prec:]
+}
+
+// roundName returns the name of a rounding function as returned by
+// RoundingMode.String, e.g. "HalfUp", or an empty string if it's not set.
+// Functions declared outside of this package are named by their package and
+// function name instead.
+func roundName(f RoundingMode) string {
+	if f == nil {
+		return ""
+	}
+	if f.modeIndex() >= 0 {
+		return f.String()
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	return strings.TrimPrefix(name, "mongo.")
+}
@@ -0,0 +1,108 @@
+package mongo
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+	"text/tabwriter"
+)
+
+func TestMoneyFormat(t *testing.T) {
+	m, _ := MoneyFromSubunits("GBP", 123456, nil)
+	n, _ := MoneyFromSubunits("GBP", -1055, RoundDown)
+	j, _ := MoneyFromSubunits("JPY", 1234, RoundHalfToEven)
+
+	tests := []struct {
+		format   string
+		value    interface{}
+		expected string
+	}{
+		{"%s", m, "£1,234.56"},
+		{"%v", m, "£1,234.56"},
+		{"%+v", m, "GBP 1234.56 HalfUp"},
+		{"%+v", n, "GBP -10.55 Floor"},
+		{"%+v", j, "JPY 1234 HalfEven"},
+		{"%q", m, `"£1,234.56"`},
+		{"%d", m, "123456"},
+		{"%d", n, "-1055"},
+		{"%+d", m, "+123456"},
+		{"%f", m, "1234.56"},
+		{"%f", n, "-10.55"},
+		{"%f", j, "1234"},
+		{"%.1f", m, "1234.6"},
+		{"%.1f", n, "-10.6"},
+		{"%.0f", m, "1235"},
+		{"%.4f", m, "1234.5600"},
+		{"%.2f", j, "1234.00"},
+		{"%+.2f", m, "+1234.56"},
+		{"%12s", m, "   £1,234.56"},
+		{"%-12s|", m, "£1,234.56   |"},
+		{"%8d", n, "   -1055"},
+		{"%08d", n, "-0001055"},
+		{"%010.2f", n, "-000010.55"},
+		{"%-8d|", n, "-1055   |"},
+		{"%x", m, "%!x(mongo.Money=£1,234.56)"},
+	}
+	for _, test := range tests {
+		str := fmt.Sprintf(test.format, test.value)
+		if str != test.expected {
+			t.Errorf("Sprintf(%q) returned '%s', expected '%s'", test.format, str, test.expected)
+		}
+	}
+}
+
+func TestMoneyFormatRounding(t *testing.T) {
+	m, _ := MoneyFromSubunits("GBP", 1025, RoundHalfToEven)
+	assert(t, fmt.Sprintf("%.1f", m) == "10.2")
+	m, _ = MoneyFromSubunits("GBP", 1035, RoundHalfToEven)
+	assert(t, fmt.Sprintf("%.1f", m) == "10.4")
+	m, _ = MoneyFromSubunits("GBP", -4, RoundHalfUp)
+	assert(t, fmt.Sprintf("%.1f", m) == "0.0")
+	m, _ = MoneyFromSubunits("GBP", 1001, RoundUp)
+	assert(t, fmt.Sprintf("%.0f", m) == "11")
+}
+
+func TestPriceFormat(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 1000, nil)
+	p.AddTaxPercent(20, "VAT")
+
+	assert(t, fmt.Sprintf("%s", p) == "£12.00")
+	assert(t, fmt.Sprintf("%v", p) == "£12.00")
+	assert(t, fmt.Sprintf("%+v", p) == "GBP 12.00 (net 10.00, tax 2.00) HalfUp")
+	assert(t, fmt.Sprintf("%d", p) == "1200")
+	assert(t, fmt.Sprintf("%.1f", p) == "12.0")
+	assert(t, fmt.Sprintf("%8s", p) == "  £12.00")
+	assert(t, fmt.Sprintf("%x", p) == "%!x(mongo.Price=£12.00)")
+}
+
+func TestBigMoneyFormat(t *testing.T) {
+	n, _ := new(big.Int).SetString("-123456789012345678901234", 10)
+	b, _ := BigMoneyFromSubunits("GBP", n, nil)
+
+	assert(t, fmt.Sprintf("%d", b) == "-123456789012345678901234")
+	assert(t, fmt.Sprintf("%f", b) == "-1234567890123456789012.34")
+	assert(t, fmt.Sprintf("%.1f", b) == "-1234567890123456789012.3")
+	assert(t, fmt.Sprintf("%+v", b) == "GBP -1234567890123456789012.34 HalfUp")
+}
+
+func TestFormatTabwriter(t *testing.T) {
+	a, _ := MoneyFromSubunits("GBP", 1055, nil)
+	b, _ := MoneyFromSubunits("GBP", 123456, nil)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 1, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "%s\t%d\t\n", a, a)
+	fmt.Fprintf(w, "%s\t%d\t\n", b, b)
+	w.Flush()
+
+	assert(t, buf.String() == "    £10.55   1055\n £1,234.56 123456\n")
+}
+
+func TestRoundName(t *testing.T) {
+	assert(t, roundName(RoundUp) == "Ceiling")
+	assert(t, roundName(RoundHalfDown) == "HalfDown")
+	custom := RoundingMode(func(f float64) int64 { return 0 })
+	assert(t, roundName(custom) == "TestRoundName.func1")
+	assert(t, roundName(nil) == "")
+}
@@ -0,0 +1,193 @@
+package mongo
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RateLookup specifies how a historical exchange rate is chosen for a date.
+type RateLookup int
+
+const (
+	// RateExact only uses a rate with the same date.
+	RateExact RateLookup = iota
+
+	// RateOnOrBefore uses the most recent rate on or before the date.
+	RateOnOrBefore
+
+	// RateInterpolate uses a rate with the same date, otherwise it linearly
+	// interpolates between the closest rates before and after the date. If
+	// there's no later rate the most recent earlier rate is used.
+	RateInterpolate
+)
+
+// HistoricalRates holds dated exchange rates so money can be converted at the
+// rate that applied on a particular date. Only the date of each rate is used,
+// the time of day is ignored. Inverse rates are derived automatically and if a
+// pivot currency is set, cross rates are derived through it. It's safe for
+// concurrent use.
+type HistoricalRates struct {
+	mu    sync.RWMutex
+	pivot string
+	rates map[[2]string][]ExchangeRate // Sorted by date.
+}
+
+// NewHistoricalRates constructs a new historical rate store containing the
+// passed rates. pivot is the ISO 4217 code of the currency used to derive
+// cross rates, or empty to disable triangulation.
+func NewHistoricalRates(pivot string, rates ...ExchangeRate) (*HistoricalRates, error) {
+	h := &HistoricalRates{
+		pivot: pivot,
+		rates: make(map[[2]string][]ExchangeRate),
+	}
+	for _, r := range rates {
+		if err := h.Add(r); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// SetPivot sets the ISO 4217 code of the currency used to derive cross rates.
+// An empty code disables triangulation.
+func (h *HistoricalRates) SetPivot(code string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pivot = code
+}
+
+// Add adds a dated exchange rate to the store. If a rate for the same currency
+// pair and date is already held it's replaced.
+func (h *HistoricalRates) Add(r ExchangeRate) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	if r.Date.IsZero() {
+		return fmt.Errorf("the exchange rate %s/%s has no date", r.Base, r.Quote)
+	}
+	r.Rate = new(big.Rat).Set(r.Rate)
+	r.Date = day(r.Date)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rates == nil {
+		h.rates = make(map[[2]string][]ExchangeRate)
+	}
+
+	key := [2]string{r.Base, r.Quote}
+	series := h.rates[key]
+	i := sort.Search(len(series), func(i int) bool {
+		return !series[i].Date.Before(r.Date)
+	})
+	if i < len(series) && series[i].Date.Equal(r.Date) {
+		series[i] = r
+		return nil
+	}
+	series = append(series, ExchangeRate{})
+	copy(series[i+1:], series[i:])
+	series[i] = r
+	h.rates[key] = series
+	return nil
+}
+
+// RateOn returns the exchange rate from the base currency to the quote
+// currency that applied on the passed date.
+func (h *HistoricalRates) RateOn(base, quote string, date time.Time, lookup RateLookup) (ExchangeRate, error) {
+	return h.On(date, lookup).Rate(base, quote)
+}
+
+// On returns a rate provider that supplies the rates that applied on the
+// passed date.
+func (h *HistoricalRates) On(date time.Time, lookup RateLookup) RateProvider {
+	h.mu.RLock()
+	pivot := h.pivot
+	h.mu.RUnlock()
+
+	return CrossRates{
+		Provider: historicalProvider{rates: h, date: day(date), lookup: lookup},
+		Pivot:    pivot,
+	}
+}
+
+// Convert converts money to another currency using the rate that applied on
+// the passed date.
+func (h *HistoricalRates) Convert(m Money, to string, date time.Time, lookup RateLookup) (Conversion, error) {
+	return ConvertDetailed(m, to, h.On(date, lookup))
+}
+
+// historicalProvider supplies the historical rates for a single date.
+type historicalProvider struct {
+	rates  *HistoricalRates
+	date   time.Time
+	lookup RateLookup
+}
+
+// Rate is an implementation of RateProvider.
+func (p historicalProvider) Rate(base, quote string) (ExchangeRate, error) {
+	if base == quote {
+		return ExchangeRate{Base: base, Quote: quote, Rate: big.NewRat(1, 1), Date: p.date}, nil
+	}
+
+	p.rates.mu.RLock()
+	defer p.rates.mu.RUnlock()
+
+	if r, ok := p.find(p.rates.rates[[2]string{base, quote}]); ok {
+		return r, nil
+	}
+	if r, ok := p.find(p.rates.rates[[2]string{quote, base}]); ok {
+		return r.Inverse(), nil
+	}
+	return ExchangeRate{}, fmt.Errorf("no %s/%s rate for %s, %w", base, quote, p.date.Format("2006-01-02"), ErrNoRate)
+}
+
+// find returns the rate from a series sorted by date that applies on the
+// provider's date.
+func (p historicalProvider) find(series []ExchangeRate) (ExchangeRate, bool) {
+	i := sort.Search(len(series), func(i int) bool {
+		return !series[i].Date.Before(p.date)
+	})
+
+	if i < len(series) && series[i].Date.Equal(p.date) {
+		return copyRate(series[i]), true
+	}
+	if p.lookup == RateExact || i == 0 {
+		return ExchangeRate{}, false
+	}
+
+	before := series[i-1]
+	if p.lookup == RateOnOrBefore || i == len(series) {
+		return copyRate(before), true
+	}
+
+	// Linearly interpolate between the rates before and after the date.
+	after := series[i]
+	elapsed := big.NewRat(int64(p.date.Sub(before.Date)/time.Hour), int64(after.Date.Sub(before.Date)/time.Hour))
+	rate := new(big.Rat).Sub(after.Rate, before.Rate)
+	rate.Mul(rate, elapsed)
+	rate.Add(rate, before.Rate)
+
+	r := ExchangeRate{
+		Base:  before.Base,
+		Quote: before.Quote,
+		Rate:  rate,
+		Date:  p.date,
+		Legs:  []ExchangeRate{copyRate(before), copyRate(after)},
+	}
+	return r, true
+}
+
+// copyRate returns a copy of an exchange rate that doesn't share its rational
+// number.
+func copyRate(r ExchangeRate) ExchangeRate {
+	r.Rate = new(big.Rat).Set(r.Rate)
+	return r
+}
+
+// day returns the date of the passed time at midnight UTC.
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,125 @@
+package mongo
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestHistoricalRatesLookup(t *testing.T) {
+	h, err := NewHistoricalRates("",
+		ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(11, 10), Date: date(2024, 1, 1)},
+		ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(14, 10), Date: date(2024, 1, 4)},
+		ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(12, 10), Date: date(2024, 1, 2)},
+	)
+	assert(t, err == nil)
+
+	tests := []struct {
+		date     time.Time
+		lookup   RateLookup
+		expected *big.Rat
+	}{
+		{date(2024, 1, 2), RateExact, big.NewRat(12, 10)},
+		{date(2024, 1, 2).Add(15 * time.Hour), RateExact, big.NewRat(12, 10)},
+		{date(2024, 1, 3), RateOnOrBefore, big.NewRat(12, 10)},
+		{date(2024, 1, 9), RateOnOrBefore, big.NewRat(14, 10)},
+		{date(2024, 1, 3), RateInterpolate, big.NewRat(13, 10)},
+		{date(2024, 1, 4), RateInterpolate, big.NewRat(14, 10)},
+		{date(2024, 1, 9), RateInterpolate, big.NewRat(14, 10)},
+	}
+	for _, test := range tests {
+		r, err := h.RateOn("EUR", "USD", test.date, test.lookup)
+		if err != nil {
+			t.Errorf("RateOn(%s, %d) failed: %s", test.date, test.lookup, err)
+			continue
+		}
+		if r.Rate.Cmp(test.expected) != 0 {
+			t.Errorf("RateOn(%s, %d) returned %s, expected %s", test.date, test.lookup, r, test.expected.RatString())
+		}
+	}
+
+	r, _ := h.RateOn("EUR", "USD", date(2024, 1, 3), RateInterpolate)
+	assert(t, r.Date.Equal(date(2024, 1, 3)) && len(r.Legs) == 2)
+
+	r, _ = h.RateOn("EUR", "USD", date(2024, 1, 3), RateOnOrBefore)
+	assert(t, r.Date.Equal(date(2024, 1, 2)))
+
+	r, _ = h.RateOn("USD", "EUR", date(2024, 1, 2), RateExact)
+	assert(t, r.Base == "USD" && r.Rate.Cmp(big.NewRat(10, 12)) == 0)
+
+	for _, lookup := range []RateLookup{RateExact, RateOnOrBefore, RateInterpolate} {
+		_, err = h.RateOn("EUR", "USD", date(2023, 12, 31), lookup)
+		assert(t, errors.Is(err, ErrNoRate))
+	}
+	_, err = h.RateOn("EUR", "USD", date(2024, 1, 3), RateExact)
+	assert(t, errors.Is(err, ErrNoRate))
+}
+
+func TestHistoricalRatesTriangulation(t *testing.T) {
+	rates, _ := ReadECBXML(strings.NewReader(ecbXML))
+	h, _ := NewHistoricalRates("EUR", rates...)
+
+	r, err := h.RateOn("GBP", "USD", date(2024, 1, 5), RateExact)
+	assert(t, err == nil)
+	assert(t, r.Rate.Cmp(new(big.Rat).Quo(big.NewRat(10921, 10000), big.NewRat(86053, 100000))) == 0)
+	assert(t, len(r.Legs) == 2 && r.Legs[0].String() == "GBP/EUR "+ratString(new(big.Rat).Inv(big.NewRat(86053, 100000))))
+	assert(t, r.Date.Equal(date(2024, 1, 5)))
+
+	m, _ := MoneyGBP(1000)
+	c, err := h.Convert(m, "USD", date(2024, 1, 4), RateExact)
+	assert(t, err == nil)
+	assertMoneyString(t, c.Money, "USD", "$12.71")
+	assert(t, c.Rate.Base == "GBP" && c.Rate.Quote == "USD" && c.Rate.Date.Equal(date(2024, 1, 4)))
+
+	c, err = h.Convert(m, "JPY", date(2024, 1, 6), RateOnOrBefore)
+	assert(t, err == nil)
+	assertMoneyString(t, c.Money, "JPY", "¥1,837")
+	assert(t, c.Rate.Date.Equal(date(2024, 1, 5)))
+
+	h.SetPivot("")
+	_, err = h.Convert(m, "USD", date(2024, 1, 5), RateExact)
+	assert(t, errors.Is(err, ErrNoRate))
+}
+
+func TestHistoricalRatesAdd(t *testing.T) {
+	h, _ := NewHistoricalRates("")
+	assert(t, h.Add(ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(11, 10)}) != nil)
+	assert(t, h.Add(ExchangeRate{Base: "EUR", Quote: "USD", Date: date(2024, 1, 1)}) != nil)
+
+	assert(t, h.Add(ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(11, 10), Date: date(2024, 1, 1)}) == nil)
+	assert(t, h.Add(ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(12, 10), Date: date(2024, 1, 1).Add(time.Hour)}) == nil)
+
+	r, _ := h.RateOn("EUR", "USD", date(2024, 1, 1), RateExact)
+	assert(t, r.Rate.Cmp(big.NewRat(12, 10)) == 0)
+}
+
+func TestCrossRates(t *testing.T) {
+	p, _ := NewMemoryRates(
+		ExchangeRate{Base: "EUR", Quote: "USD", Rate: big.NewRat(11, 10), Date: date(2024, 1, 2)},
+		ExchangeRate{Base: "EUR", Quote: "GBP", Rate: big.NewRat(4, 5), Date: date(2024, 1, 1)},
+	)
+	cross := CrossRates{Provider: p, Pivot: "EUR"}
+
+	r, err := cross.Rate("GBP", "USD")
+	assert(t, err == nil)
+	assert(t, r.Rate.Cmp(big.NewRat(11, 8)) == 0)
+	assert(t, r.Date.Equal(date(2024, 1, 1)))
+
+	r, err = cross.Rate("EUR", "USD")
+	assert(t, err == nil && len(r.Legs) == 0)
+
+	_, err = cross.Rate("GBP", "JPY")
+	assert(t, errors.Is(err, ErrNoRate))
+
+	m, _ := MoneyGBP(800)
+	c, err := ConvertDetailed(m, "USD", cross)
+	assert(t, err == nil)
+	assertMoneyString(t, c.Money, "USD", "$11.00")
+	assert(t, c.Rate.String() == "GBP/USD 1.375")
+}
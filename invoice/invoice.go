@@ -0,0 +1,345 @@
+// Package invoice builds invoices from mongo prices, with line items, tax
+// summaries by rate and totals that reconcile with the lines.
+package invoice
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/nomad-software/mongo"
+)
+
+// RoundingPolicy specifies when the taxes of an invoice are rounded.
+type RoundingPolicy int
+
+const (
+	// RoundPerLine calculates and rounds the taxes of each line separately.
+	// The invoice totals are the sums of the line totals.
+	RoundPerLine RoundingPolicy = iota
+
+	// RoundPerRate totals the amounts each tax is charged on across the lines
+	// before calculating the tax, so each tax is rounded once per rate. The
+	// rounding difference of an exclusive tax is added to the gross and that
+	// of an inclusive tax is kept in the net, so the invoice totals may differ
+	// from the sums of the line totals.
+	RoundPerRate
+)
+
+// String is an implementation of fmt.Stringer.
+func (r RoundingPolicy) String() string {
+	switch r {
+	case RoundPerLine:
+		return "RoundPerLine"
+	case RoundPerRate:
+		return "RoundPerRate"
+	}
+	return fmt.Sprintf("RoundingPolicy(%d)", int(r))
+}
+
+// MarshalText is an implementation of encoding.TextMarshaler, e.g. "line".
+func (r RoundingPolicy) MarshalText() ([]byte, error) {
+	switch r {
+	case RoundPerLine:
+		return []byte("line"), nil
+	case RoundPerRate:
+		return []byte("rate"), nil
+	}
+	return nil, fmt.Errorf("failed to marshal rounding policy %d", int(r))
+}
+
+// UnmarshalText is an implementation of encoding.TextUnmarshaler.
+func (r *RoundingPolicy) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "line":
+		*r = RoundPerLine
+	case "rate":
+		*r = RoundPerRate
+	default:
+		return fmt.Errorf("failed to unmarshal rounding policy '%s'", text)
+	}
+	return nil
+}
+
+// Invoice is a list of line items in a single currency.
+type Invoice struct {
+	ID        string           // The identifier of the invoice.
+	Date      time.Time        // The date of the invoice.
+	Currency  string           // The ISO 4217 code of the invoice currency.
+	Rounding  RoundingPolicy   // When the taxes are rounded.
+	Lines     []Line           // The line items.
+	Discounts []mongo.Discount // The order level discounts, allocated across the lines in order.
+}
+
+// New constructs a new empty invoice.
+func New(id string, currency string, date time.Time, rounding RoundingPolicy) (*Invoice, error) {
+	if _, ok := mongo.LookupCurrency(currency); !ok {
+		return nil, fmt.Errorf("failed to create invoice '%s', the currency code '%s' is not recognised", id, currency)
+	}
+	if rounding != RoundPerLine && rounding != RoundPerRate {
+		return nil, fmt.Errorf("failed to create invoice '%s', invalid rounding policy %d", id, rounding)
+	}
+	return &Invoice{ID: id, Date: date, Currency: currency, Rounding: rounding}, nil
+}
+
+// AddLine adds a line item to the invoice. The line's unit price must be in
+// the invoice currency and its taxes must be valid.
+func (inv *Invoice) AddLine(l Line) error {
+	if err := inv.checkLine(l); err != nil {
+		return err
+	}
+	inv.Lines = append(inv.Lines, l)
+	return nil
+}
+
+// AddDiscount adds an order level discount to the invoice. The discount is
+// calculated on the invoice total and allocated across the lines in proportion
+// to their price, so the taxes of each line go down with it.
+func (inv *Invoice) AddDiscount(d mongo.Discount) error {
+	next := *inv
+	next.Discounts = append(append([]mongo.Discount{}, inv.Discounts...), d)
+	if _, err := next.Totals(); err != nil {
+		return fmt.Errorf("failed to add discount '%s', %w", d.Description, err)
+	}
+	inv.Discounts = next.Discounts
+	return nil
+}
+
+// checkLine checks the line can be added to the invoice.
+func (inv Invoice) checkLine(l Line) error {
+	if l.UnitPrice.IsoCode() != inv.Currency {
+		return fmt.Errorf("invalid line '%s', currency '%s' does not match '%s', %w", l.Description, l.UnitPrice.IsoCode(), inv.Currency, mongo.ErrCurrencyMismatch)
+	}
+	if _, err := l.Total(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Totals returns the totals of the invoice using its rounding policy.
+func (inv Invoice) Totals() (Totals, error) {
+	switch inv.Rounding {
+	case RoundPerLine:
+		return inv.totalPerLine()
+	case RoundPerRate:
+		return inv.totalPerRate()
+	}
+	return Totals{}, fmt.Errorf("failed to total invoice '%s', invalid rounding policy %d", inv.ID, inv.Rounding)
+}
+
+// totalPerLine returns the totals of the invoice with the taxes of each line
+// rounded separately.
+func (inv Invoice) totalPerLine() (Totals, error) {
+	prices := make([]mongo.Price, 0, len(inv.Lines))
+	for _, l := range inv.Lines {
+		if err := inv.checkLine(l); err != nil {
+			return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+		}
+		p, _ := l.Total()
+		prices = append(prices, p)
+	}
+	return inv.sum(prices)
+}
+
+// totalPerRate returns the totals of the invoice with each tax calculated once
+// on the sum of its bases in the lines. Per unit taxes are fixed amounts so
+// they're the same as when each line is rounded separately.
+func (inv Invoice) totalPerRate() (Totals, error) {
+	totals, err := inv.totalPerLine()
+	if err != nil {
+		return Totals{}, err
+	}
+	for i, s := range totals.Taxes {
+		if s.Tax.Base == mongo.TaxPerUnit {
+			continue
+		}
+		amount, err := rateOf(s.Base, s.Tax)
+		if err != nil {
+			return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+		}
+		diff, err := amount.SubE(s.Amount)
+		if err != nil {
+			return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+		}
+		if totals.Tax, err = totals.Tax.AddE(diff); err != nil {
+			return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+		}
+		if s.Tax.Mode == mongo.TaxInclusive {
+			totals.Net, err = totals.Net.SubE(diff)
+		} else {
+			totals.Gross, err = totals.Gross.AddE(diff)
+		}
+		if err != nil {
+			return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+		}
+		totals.Taxes[i].Amount = amount
+	}
+	return totals, nil
+}
+
+// rateOf returns the passed tax's percentage of the base, rounded once.
+func rateOf(base mongo.Money, t mongo.Tax) (mongo.Money, error) {
+	num, den := t.Rate.Num(), new(big.Int).Mul(t.Rate.Denom(), big.NewInt(100))
+	if !num.IsInt64() || !den.IsInt64() {
+		return mongo.Money{}, fmt.Errorf("failed to calculate tax '%s', %w", t.Description, mongo.ErrOverflow)
+	}
+	amount, err := base.MulRat(num.Int64(), den.Int64())
+	if err != nil {
+		return mongo.Money{}, fmt.Errorf("failed to calculate tax '%s', %w", t.Description, err)
+	}
+	return amount, nil
+}
+
+// sum allocates the order level discounts across the passed prices and returns
+// their totals with a summary of each tax.
+func (inv Invoice) sum(prices []mongo.Price) (Totals, error) {
+	total, err := mongo.PriceFromSubunits(inv.Currency, 0, nil)
+	if err != nil {
+		return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+	}
+
+	if len(prices) > 0 {
+		for _, d := range inv.Discounts {
+			if prices, err = mongo.AllocateDiscount(prices, d); err != nil {
+				return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+			}
+		}
+	}
+
+	var summary []TaxSummary
+	for _, p := range prices {
+		if total, err = total.AddE(p); err != nil {
+			return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+		}
+		bases, err := p.TaxBases()
+		if err != nil {
+			return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+		}
+		for i, line := range p.TaxLines() {
+			if summary, err = addSummary(summary, line, bases[i]); err != nil {
+				return Totals{}, fmt.Errorf("failed to total invoice '%s', %w", inv.ID, err)
+			}
+		}
+	}
+
+	return Totals{
+		Net:      total.Net(),
+		Tax:      total.Tax(),
+		Gross:    total.Gross(),
+		Discount: total.Discount(),
+		Taxes:    summary,
+	}, nil
+}
+
+// Reconcile compares the taxes of the invoice with the sums of the taxes of
+// its lines, each rounded separately, and reports any differences.
+func (inv Invoice) Reconcile() (Reconciliation, error) {
+	totals, err := inv.Totals()
+	if err != nil {
+		return Reconciliation{}, err
+	}
+	perLine, err := inv.totalPerLine()
+	if err != nil {
+		return Reconciliation{}, err
+	}
+
+	r := Reconciliation{Rounding: inv.Rounding}
+	for _, s := range totals.Taxes {
+		lines := s.Amount.Clone(0)
+		if i := findSummary(perLine.Taxes, s.Tax); i >= 0 {
+			lines = perLine.Taxes[i].Amount
+		}
+		r.Taxes = append(r.Taxes, TaxDifference{
+			Tax:        s.Tax,
+			Invoice:    s.Amount,
+			Lines:      lines,
+			Difference: s.Amount.Sub(lines),
+		})
+	}
+	r.Net = TotalDifference{Invoice: totals.Net, Lines: perLine.Net, Difference: totals.Net.Sub(perLine.Net)}
+	r.Tax = TotalDifference{Invoice: totals.Tax, Lines: perLine.Tax, Difference: totals.Tax.Sub(perLine.Tax)}
+	r.Gross = TotalDifference{Invoice: totals.Gross, Lines: perLine.Gross, Difference: totals.Gross.Sub(perLine.Gross)}
+	r.Discount = TotalDifference{Invoice: totals.Discount, Lines: perLine.Discount, Difference: totals.Discount.Sub(perLine.Discount)}
+	return r, nil
+}
+
+// invoiceJSON is the JSON representation of an invoice.
+type invoiceJSON struct {
+	ID        string           `json:"id"`
+	Date      string           `json:"date,omitempty"`
+	Currency  string           `json:"currency"`
+	Rounding  RoundingPolicy   `json:"rounding"`
+	Lines     []Line           `json:"lines"`
+	Discounts []mongo.Discount `json:"discounts,omitempty"`
+	Totals    *Totals          `json:"totals,omitempty"`
+}
+
+// MarshalJSON is an implementation of json.Marshaller. The totals of the
+// invoice are included for convenience.
+func (inv Invoice) MarshalJSON() ([]byte, error) {
+	totals, err := inv.Totals()
+	if err != nil {
+		return nil, err
+	}
+	v := invoiceJSON{
+		ID:        inv.ID,
+		Currency:  inv.Currency,
+		Rounding:  inv.Rounding,
+		Lines:     inv.Lines,
+		Discounts: inv.Discounts,
+		Totals:    &totals,
+	}
+	if v.Lines == nil {
+		v.Lines = []Line{}
+	}
+	if !inv.Date.IsZero() {
+		v.Date = inv.Date.Format(time.RFC3339)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. If totals are
+// present their gross must equal the gross of the invoice.
+func (inv *Invoice) UnmarshalJSON(b []byte) error {
+	var v invoiceJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal invoice, %w", err)
+	}
+
+	var date time.Time
+	if v.Date != "" {
+		var err error
+		if date, err = time.Parse(time.RFC3339, v.Date); err != nil {
+			return fmt.Errorf("failed to unmarshal invoice '%s', %w", v.ID, err)
+		}
+	}
+
+	result, err := New(v.ID, v.Currency, date, v.Rounding)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal invoice, %w", err)
+	}
+	for _, l := range v.Lines {
+		if err := result.AddLine(l); err != nil {
+			return fmt.Errorf("failed to unmarshal invoice '%s', %w", v.ID, err)
+		}
+	}
+	for _, d := range v.Discounts {
+		if err := result.AddDiscount(d); err != nil {
+			return fmt.Errorf("failed to unmarshal invoice '%s', %w", v.ID, err)
+		}
+	}
+
+	if v.Totals != nil {
+		totals, err := result.Totals()
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal invoice, %w", err)
+		}
+		if !sameMoney(v.Totals.Gross, totals.Gross) {
+			return fmt.Errorf("failed to unmarshal invoice '%s', total %s does not equal %s", v.ID, v.Totals.Gross, totals.Gross)
+		}
+	}
+
+	*inv = *result
+	return nil
+}
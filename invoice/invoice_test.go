@@ -0,0 +1,360 @@
+package invoice
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nomad-software/mongo"
+)
+
+func newTestInvoice(t *testing.T, rounding RoundingPolicy) *Invoice {
+	inv, err := New("INV-1", "GBP", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), rounding)
+	if err != nil {
+		t.Fatalf("New failed: %s", err)
+	}
+	lines := []Line{
+		{Description: "Widget", Quantity: 1, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}},
+		{Description: "Gadget", Quantity: 1, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}},
+		{Description: "Gizmo", Quantity: 1, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}},
+		{Description: "Book", Quantity: 2, UnitPrice: price(1050), Taxes: []mongo.Tax{vat("0")}},
+	}
+	for _, l := range lines {
+		if err := inv.AddLine(l); err != nil {
+			t.Fatalf("AddLine failed: %s", err)
+		}
+	}
+	return inv
+}
+
+func TestNewInvoice(t *testing.T) {
+	if _, err := New("INV-1", "XXX", time.Time{}, RoundPerLine); err == nil {
+		t.Errorf("New failed to return an error for an unknown currency")
+	}
+	if _, err := New("INV-1", "GBP", time.Time{}, 5); err == nil {
+		t.Errorf("New failed to return an error for an invalid rounding policy")
+	}
+}
+
+func TestInvoiceAddLine(t *testing.T) {
+	inv, _ := New("INV-1", "GBP", time.Time{}, RoundPerLine)
+
+	usd, _ := mongo.PriceFromSubunits("USD", 100, nil)
+	err := inv.AddLine(Line{Description: "Dollars", Quantity: 1, UnitPrice: usd})
+	if !errors.Is(err, mongo.ErrCurrencyMismatch) {
+		t.Errorf("AddLine failed to return ErrCurrencyMismatch, returned %v", err)
+	}
+	if err := inv.AddLine(Line{Description: "None", UnitPrice: price(100)}); err == nil {
+		t.Errorf("AddLine failed to return an error for a zero quantity")
+	}
+	bad := mongo.Tax{Description: "Bad", Base: mongo.TaxOnNet}
+	if err := inv.AddLine(Line{Description: "Bad", Quantity: 1, UnitPrice: price(100), Taxes: []mongo.Tax{bad}}); err == nil {
+		t.Errorf("AddLine failed to return an error for an invalid tax")
+	}
+	if len(inv.Lines) != 0 {
+		t.Errorf("AddLine added %d invalid lines", len(inv.Lines))
+	}
+}
+
+func TestInvoiceTotalsPerLine(t *testing.T) {
+	inv := newTestInvoice(t, RoundPerLine)
+	totals, err := inv.Totals()
+	if err != nil {
+		t.Fatalf("Totals failed: %s", err)
+	}
+	if totals.Net.Value() != 2397 || totals.Tax.Value() != 60 || totals.Gross.Value() != 2457 {
+		t.Errorf("Totals returned net %s, tax %s, gross %s", totals.Net, totals.Tax, totals.Gross)
+	}
+	if len(totals.Taxes) != 2 {
+		t.Fatalf("Totals returned %d tax summaries", len(totals.Taxes))
+	}
+	if s := totals.Taxes[0]; s.Tax.Label() != "VAT 20%" || s.Base.Value() != 297 || s.Amount.Value() != 60 {
+		t.Errorf("Totals returned summary %s base %s amount %s", s.Tax, s.Base, s.Amount)
+	}
+	if s := totals.Taxes[1]; s.Tax.Label() != "VAT 0%" || s.Base.Value() != 2100 || s.Amount.Value() != 0 {
+		t.Errorf("Totals returned summary %s base %s amount %s", s.Tax, s.Base, s.Amount)
+	}
+}
+
+func TestInvoiceTotalsPerRate(t *testing.T) {
+	inv := newTestInvoice(t, RoundPerRate)
+	totals, err := inv.Totals()
+	if err != nil {
+		t.Fatalf("Totals failed: %s", err)
+	}
+	if totals.Net.Value() != 2397 || totals.Tax.Value() != 59 || totals.Gross.Value() != 2456 {
+		t.Errorf("Totals returned net %s, tax %s, gross %s", totals.Net, totals.Tax, totals.Gross)
+	}
+	if len(totals.Taxes) != 2 {
+		t.Fatalf("Totals returned %d tax summaries", len(totals.Taxes))
+	}
+	if s := totals.Taxes[0]; s.Base.Value() != 297 || s.Amount.Value() != 59 {
+		t.Errorf("Totals returned summary %s base %s amount %s", s.Tax, s.Base, s.Amount)
+	}
+}
+
+func TestInvoiceTotalsPerRateByTax(t *testing.T) {
+	levy, _ := mongo.NetTax("Levy", "1")
+	gst, _ := mongo.NetTax("GST", "5")
+	qst, _ := mongo.CompoundTax("QST", "9.975", "GST")
+	discount, _ := mongo.PercentDiscount("Staff", "10")
+
+	inv, _ := New("INV-5", "GBP", time.Time{}, RoundPerRate)
+	lines := []Line{
+		{Description: "Widget", Quantity: 1, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20"), levy}},
+		{Description: "Gadget", Quantity: 1, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}},
+		{Description: "Gizmo", Quantity: 1, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}},
+		{Description: "Doohickey", Quantity: 2, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}, Discounts: []mongo.Discount{discount}},
+		{Description: "Maple syrup", Quantity: 1, UnitPrice: price(1000), Taxes: []mongo.Tax{gst, qst}},
+	}
+	for _, l := range lines {
+		if err := inv.AddLine(l); err != nil {
+			t.Fatalf("AddLine failed: %s", err)
+		}
+	}
+	totals, err := inv.Totals()
+	if err != nil {
+		t.Fatalf("Totals failed: %s", err)
+	}
+
+	expected := []struct {
+		label  string
+		base   int64
+		amount int64
+	}{
+		{"VAT 20%", 475, 95},
+		{"Levy 1%", 99, 1},
+		{"GST 5%", 1000, 50},
+		{"QST 9.975%", 1050, 105},
+	}
+	if len(totals.Taxes) != len(expected) {
+		t.Fatalf("Totals returned %d tax summaries", len(totals.Taxes))
+	}
+	for i, e := range expected {
+		if s := totals.Taxes[i]; s.Tax.Label() != e.label || s.Base.Value() != e.base || s.Amount.Value() != e.amount {
+			t.Errorf("Totals returned summary %s base %s amount %s", s.Tax, s.Base, s.Amount)
+		}
+	}
+	if totals.Net.Value() != 1475 || totals.Tax.Value() != 251 || totals.Gross.Value() != 1726 {
+		t.Errorf("Totals returned net %s, tax %s, gross %s", totals.Net, totals.Tax, totals.Gross)
+	}
+}
+
+func TestInvoiceTotalsInclusive(t *testing.T) {
+	inc := vat("20")
+	inc.Mode = mongo.TaxInclusive
+
+	inv, _ := New("INV-2", "GBP", time.Time{}, RoundPerRate)
+	for i := 0; i < 3; i++ {
+		inv.AddLine(Line{Description: "Coffee", Quantity: 1, UnitPrice: price(250), Taxes: []mongo.Tax{inc}})
+	}
+	totals, err := inv.Totals()
+	if err != nil {
+		t.Fatalf("Totals failed: %s", err)
+	}
+	if totals.Gross.Value() != 750 || totals.Tax.Value() != 125 || totals.Net.Value() != 625 {
+		t.Errorf("Totals returned net %s, tax %s, gross %s", totals.Net, totals.Tax, totals.Gross)
+	}
+}
+
+func TestInvoiceReconcile(t *testing.T) {
+	r, err := newTestInvoice(t, RoundPerLine).Reconcile()
+	if err != nil {
+		t.Fatalf("Reconcile failed: %s", err)
+	}
+	if !r.Balanced() {
+		t.Errorf("Reconcile returned an unbalanced report for per line rounding")
+	}
+
+	r, err = newTestInvoice(t, RoundPerRate).Reconcile()
+	if err != nil {
+		t.Fatalf("Reconcile failed: %s", err)
+	}
+	if r.Balanced() {
+		t.Errorf("Reconcile returned a balanced report for per rate rounding")
+	}
+	if r.Tax.Invoice.Value() != 59 || r.Tax.Lines.Value() != 60 || r.Tax.Difference.Value() != -1 {
+		t.Errorf("Reconcile returned tax %+v", r.Tax)
+	}
+	if r.Gross.Difference.Value() != -1 || !r.Net.Difference.IsZero() {
+		t.Errorf("Reconcile returned gross %+v net %+v", r.Gross, r.Net)
+	}
+	if len(r.Taxes) != 2 || r.Taxes[0].Difference.Value() != -1 || !r.Taxes[1].Difference.IsZero() {
+		t.Errorf("Reconcile returned taxes %+v", r.Taxes)
+	}
+}
+
+func TestRoundingPolicyText(t *testing.T) {
+	for _, r := range []RoundingPolicy{RoundPerLine, RoundPerRate} {
+		b, err := r.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText failed: %s", err)
+		}
+		var result RoundingPolicy
+		if err := result.UnmarshalText(b); err != nil || result != r {
+			t.Errorf("UnmarshalText returned %s, %v", result, err)
+		}
+	}
+	if _, err := RoundingPolicy(5).MarshalText(); err == nil {
+		t.Errorf("MarshalText failed to return an error")
+	}
+	var r RoundingPolicy
+	if err := r.UnmarshalText([]byte("never")); err == nil {
+		t.Errorf("UnmarshalText failed to return an error")
+	}
+}
+
+func TestInvoiceJSON(t *testing.T) {
+	inv := newTestInvoice(t, RoundPerRate)
+	b, err := json.Marshal(inv)
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+
+	var result Invoice
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+	if result.ID != inv.ID || !result.Date.Equal(inv.Date) || result.Currency != "GBP" || result.Rounding != RoundPerRate || len(result.Lines) != 4 {
+		t.Errorf("Unmarshal returned %+v", result)
+	}
+	again, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+	if string(again) != string(b) {
+		t.Errorf("JSON round trip changed the invoice:\n%s\n%s", b, again)
+	}
+
+	// The totals are checked against the lines.
+	var v map[string]any
+	json.Unmarshal(b, &v)
+	v["rounding"] = "line"
+	tampered, _ := json.Marshal(v)
+	if err := json.Unmarshal(tampered, &result); err == nil {
+		t.Errorf("Unmarshal failed to return an error for wrong totals")
+	}
+
+	empty, _ := New("INV-3", "GBP", time.Time{}, RoundPerLine)
+	b, err = json.Marshal(empty)
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+	if err := json.Unmarshal(b, &result); err != nil || len(result.Lines) != 0 || !result.Date.IsZero() {
+		t.Errorf("Unmarshal returned %+v, %v", result, err)
+	}
+}
+
+func TestInvoiceDiscounts(t *testing.T) {
+	for _, rounding := range []RoundingPolicy{RoundPerLine, RoundPerRate} {
+		inv, _ := New("INV-4", "GBP", time.Time{}, rounding)
+
+		widget := Line{Description: "Widget", Quantity: 3, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}}
+		offer, err := widget.FreeUnits("Buy 2 get 1 free", 2, 1)
+		if err != nil {
+			t.Fatalf("FreeUnits failed: %s", err)
+		}
+		widget.Discounts = []mongo.Discount{offer}
+		if err := inv.AddLine(widget); err != nil {
+			t.Fatalf("AddLine failed: %s", err)
+		}
+		if err := inv.AddLine(Line{Description: "Book", Quantity: 2, UnitPrice: price(1050), Taxes: []mongo.Tax{vat("0")}}); err != nil {
+			t.Fatalf("AddLine failed: %s", err)
+		}
+
+		d := mongo.AmountDiscount("£5 off orders over £20", gbp(500))
+		d.Minimum = gbp(2000)
+		if err := inv.AddDiscount(d); err != nil {
+			t.Fatalf("AddDiscount failed: %s", err)
+		}
+
+		totals, err := inv.Totals()
+		if err != nil {
+			t.Fatalf("Totals failed: %s", err)
+		}
+		if totals.Net.Value() != 1798 || totals.Tax.Value() != 31 || totals.Gross.Value() != 1829 || totals.Discount.Value() != 599 {
+			t.Errorf("Totals returned net %s, tax %s, gross %s, discount %s", totals.Net, totals.Tax, totals.Gross, totals.Discount)
+		}
+		if s := totals.Taxes[0]; s.Base.Value() != 154 || s.Amount.Value() != 31 {
+			t.Errorf("Totals returned summary %s base %s amount %s", s.Tax, s.Base, s.Amount)
+		}
+
+		r, err := inv.Reconcile()
+		if err != nil {
+			t.Fatalf("Reconcile failed: %s", err)
+		}
+		if !r.Balanced() {
+			t.Errorf("Reconcile returned an unbalanced report for %s", rounding)
+		}
+	}
+}
+
+func TestInvoiceDiscountsPerRate(t *testing.T) {
+	inv := newTestInvoice(t, RoundPerRate)
+	d, _ := mongo.PercentDiscount("Loyalty", "10")
+	if err := inv.AddDiscount(d); err != nil {
+		t.Fatalf("AddDiscount failed: %s", err)
+	}
+	totals, err := inv.Totals()
+	if err != nil {
+		t.Fatalf("Totals failed: %s", err)
+	}
+	if totals.Net.Value() != 2157 || totals.Discount.Value() != 240 || totals.Tax.Value() != 53 {
+		t.Errorf("Totals returned net %s, tax %s, discount %s", totals.Net, totals.Tax, totals.Discount)
+	}
+
+	bad := mongo.AmountDiscount("Euros", mongo.Money{})
+	if err := inv.AddDiscount(bad); err == nil {
+		t.Errorf("AddDiscount failed to return an error for an invalid discount")
+	}
+	if len(inv.Discounts) != 1 {
+		t.Errorf("AddDiscount added an invalid discount")
+	}
+}
+
+func TestLineFreeUnits(t *testing.T) {
+	l := Line{Description: "Widget", Quantity: 7, UnitPrice: price(100), Taxes: []mongo.Tax{vat("20")}}
+	d, err := l.FreeUnits("3 for 2", 2, 1)
+	if err != nil {
+		t.Fatalf("FreeUnits failed: %s", err)
+	}
+	if d.Amount.Value() != 200 || d.Timing != mongo.DiscountBeforeTax {
+		t.Errorf("FreeUnits returned %s", d)
+	}
+
+	inc := vat("20")
+	inc.Mode = mongo.TaxInclusive
+	l.Taxes = []mongo.Tax{inc}
+	if d, _ := l.FreeUnits("3 for 2", 2, 1); d.Timing != mongo.DiscountAfterTax {
+		t.Errorf("FreeUnits returned %s", d)
+	}
+
+	if _, err := l.FreeUnits("Nothing free", 2, 0); err == nil {
+		t.Errorf("FreeUnits failed to return an error")
+	}
+}
+
+func TestInvoiceJSONWithDiscounts(t *testing.T) {
+	inv := newTestInvoice(t, RoundPerLine)
+	inv.Lines[3].Discounts = []mongo.Discount{mongo.OverrideDiscount("Price match", gbp(1800))}
+	d, _ := mongo.PercentDiscount("Loyalty", "10")
+	d.Timing = mongo.DiscountAfterTax
+	inv.AddDiscount(d)
+
+	b, err := json.Marshal(inv)
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+	var result Invoice
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+	if len(result.Discounts) != 1 || len(result.Lines[3].Discounts) != 1 {
+		t.Errorf("Unmarshal returned %+v", result)
+	}
+	again, _ := json.Marshal(result)
+	if string(again) != string(b) {
+		t.Errorf("JSON round trip changed the invoice:\n%s\n%s", b, again)
+	}
+}
@@ -0,0 +1,130 @@
+package invoice
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nomad-software/mongo"
+)
+
+// Line is a line item of an invoice.
+type Line struct {
+	Description string           // The description of the item.
+	Quantity    int64            // The number of units, which must be greater than zero.
+	UnitPrice   mongo.Price      // The price of a single unit before the line's taxes are applied.
+	Taxes       []mongo.Tax      // The taxes applied to the line, in order.
+	Discounts   []mongo.Discount // The discounts taken off the line after its taxes are applied, in order.
+}
+
+// Total returns the price of the line, which is the unit price multiplied by
+// the quantity with the line's taxes applied according to their mode, then
+// the line's discounts taken off. Taxes are rounded once for the whole line.
+func (l Line) Total() (mongo.Price, error) {
+	if err := l.validate(); err != nil {
+		return mongo.Price{}, err
+	}
+	p, err := l.UnitPrice.MulE(l.Quantity)
+	if err != nil {
+		return mongo.Price{}, fmt.Errorf("failed to total line '%s', %w", l.Description, err)
+	}
+	if err := p.ApplyTaxes(l.Taxes...); err != nil {
+		return mongo.Price{}, fmt.Errorf("failed to total line '%s', %w", l.Description, err)
+	}
+	if err := p.ApplyDiscounts(l.Discounts...); err != nil {
+		return mongo.Price{}, fmt.Errorf("failed to total line '%s', %w", l.Description, err)
+	}
+	return p, nil
+}
+
+// FreeUnits returns a discount for a multi-buy offer based on the line's
+// current quantity, e.g. buy 2 get 1 free. The discount is the unit price for
+// each free unit, taken off before tax, or after tax if any of the line's taxes
+// are included in the unit price.
+func (l Line) FreeUnits(desc string, buy int64, free int64) (mongo.Discount, error) {
+	if buy < 0 || free <= 0 {
+		return mongo.Discount{}, fmt.Errorf("failed to create discount '%s', invalid offer buy %d get %d free", desc, buy, free)
+	}
+	if err := l.validate(); err != nil {
+		return mongo.Discount{}, err
+	}
+	amount, err := l.UnitPrice.Gross().MulE(l.Quantity / (buy + free) * free)
+	if err != nil {
+		return mongo.Discount{}, fmt.Errorf("failed to create discount '%s', %w", desc, err)
+	}
+	d := mongo.AmountDiscount(desc, amount)
+	for _, t := range l.Taxes {
+		if t.Mode == mongo.TaxInclusive {
+			d.Timing = mongo.DiscountAfterTax
+		}
+	}
+	return d, nil
+}
+
+// validate checks the line can be totalled.
+func (l Line) validate() error {
+	if l.Quantity <= 0 {
+		return fmt.Errorf("invalid line '%s', quantity %d must be greater than zero", l.Description, l.Quantity)
+	}
+	if l.UnitPrice.IsoCode() == "" {
+		return fmt.Errorf("invalid line '%s', no unit price specified", l.Description)
+	}
+	return nil
+}
+
+// lineJSON is the JSON representation of a line.
+type lineJSON struct {
+	Description string           `json:"description"`
+	Quantity    int64            `json:"quantity"`
+	UnitPrice   mongo.Price      `json:"unitPrice"`
+	Taxes       []mongo.Tax      `json:"taxes,omitempty"`
+	Discounts   []mongo.Discount `json:"discounts,omitempty"`
+	Total       *mongo.Price     `json:"total,omitempty"`
+}
+
+// MarshalJSON is an implementation of json.Marshaller. The total of the line
+// is included for convenience.
+func (l Line) MarshalJSON() ([]byte, error) {
+	total, err := l.Total()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(lineJSON{
+		Description: l.Description,
+		Quantity:    l.Quantity,
+		UnitPrice:   l.UnitPrice,
+		Taxes:       l.Taxes,
+		Discounts:   l.Discounts,
+		Total:       &total,
+	})
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. If a total is
+// present its gross must equal the total of the line.
+func (l *Line) UnmarshalJSON(b []byte) error {
+	var v lineJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal line, %w", err)
+	}
+	line := Line{
+		Description: v.Description,
+		Quantity:    v.Quantity,
+		UnitPrice:   v.UnitPrice,
+		Taxes:       v.Taxes,
+		Discounts:   v.Discounts,
+	}
+	total, err := line.Total()
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal line, %w", err)
+	}
+	if v.Total != nil && !sameMoney(v.Total.Gross(), total.Gross()) {
+		return fmt.Errorf("failed to unmarshal line '%s', total %s does not equal %s", line.Description, v.Total.Gross(), total.Gross())
+	}
+	*l = line
+	return nil
+}
+
+// sameMoney returns true if both money objects have the same currency and
+// value.
+func sameMoney(a, b mongo.Money) bool {
+	return a.IsoCode() == b.IsoCode() && a.Value() == b.Value()
+}
@@ -0,0 +1,69 @@
+package invoice
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nomad-software/mongo"
+)
+
+func gbp(value int64) mongo.Money {
+	m, _ := mongo.MoneyGBP(value)
+	return m
+}
+
+func price(value int64) mongo.Price {
+	p, _ := mongo.PriceFromSubunits("GBP", value, nil)
+	return p
+}
+
+func vat(percent string) mongo.Tax {
+	t, _ := mongo.NetTax("VAT", percent)
+	t.Jurisdiction = "GB"
+	return t
+}
+
+func TestLineTotal(t *testing.T) {
+	l := Line{Description: "Widget", Quantity: 3, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}}
+	p, err := l.Total()
+	if err != nil {
+		t.Fatalf("Total failed: %s", err)
+	}
+	if p.Net().Value() != 297 || p.Tax().Value() != 59 || p.Gross().Value() != 356 {
+		t.Errorf("Total returned %s (net %s, tax %s)", p.Gross(), p.Net(), p.Tax())
+	}
+
+	invalid := []Line{
+		{Description: "No quantity", UnitPrice: price(99)},
+		{Description: "Negative quantity", Quantity: -1, UnitPrice: price(99)},
+		{Description: "No price", Quantity: 1},
+	}
+	for _, l := range invalid {
+		if _, err := l.Total(); err == nil {
+			t.Errorf("Total failed to return an error for '%s'", l.Description)
+		}
+	}
+}
+
+func TestLineJSON(t *testing.T) {
+	l := Line{Description: "Widget", Quantity: 3, UnitPrice: price(99), Taxes: []mongo.Tax{vat("20")}}
+	b, err := json.Marshal(l)
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+
+	var result Line
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+	p, _ := result.Total()
+	if result.Description != "Widget" || result.Quantity != 3 || p.Gross().Value() != 356 {
+		t.Errorf("Unmarshal returned %+v", result)
+	}
+
+	tampered := strings.Replace(string(b), `"quantity":3`, `"quantity":4`, 1)
+	if err := json.Unmarshal([]byte(tampered), &result); err == nil {
+		t.Errorf("Unmarshal failed to return an error for a wrong total")
+	}
+}
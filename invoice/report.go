@@ -0,0 +1,97 @@
+package invoice
+
+import (
+	"github.com/nomad-software/mongo"
+)
+
+// Totals are the totals of an invoice.
+type Totals struct {
+	Net      mongo.Money  `json:"net"`      // The total before tax.
+	Tax      mongo.Money  `json:"tax"`      // The total tax.
+	Gross    mongo.Money  `json:"gross"`    // The total including tax.
+	Discount mongo.Money  `json:"discount"` // The total taken off by line and order level discounts.
+	Taxes    []TaxSummary `json:"taxes"`    // The total of each tax, in the order they first appear.
+}
+
+// TaxSummary is the total of a single tax rate across an invoice.
+type TaxSummary struct {
+	Tax    mongo.Tax   `json:"tax"`    // The definition of the tax.
+	Base   mongo.Money `json:"base"`   // The total the tax is charged on, i.e. the net plus any earlier taxes given by its base. Zero for per unit taxes.
+	Amount mongo.Money `json:"amount"` // The total amount of the tax.
+}
+
+// sameTax returns true if both taxes are summarised together.
+func sameTax(a, b mongo.Tax) bool {
+	if a.ID != "" || b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Jurisdiction == b.Jurisdiction && a.Label() == b.Label()
+}
+
+// findSummary returns the index of the summary of the passed tax or -1 if
+// there isn't one.
+func findSummary(s []TaxSummary, t mongo.Tax) int {
+	for i := range s {
+		if sameTax(s[i].Tax, t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// addSummary adds a tax line charged on the passed base to the summaries.
+func addSummary(s []TaxSummary, line mongo.TaxLine, base mongo.Money) ([]TaxSummary, error) {
+	i := findSummary(s, line.Tax)
+	if i < 0 {
+		return append(s, TaxSummary{Tax: line.Tax, Base: base, Amount: line.Amount}), nil
+	}
+	var err error
+	if s[i].Base, err = s[i].Base.AddE(base); err != nil {
+		return nil, err
+	}
+	if s[i].Amount, err = s[i].Amount.AddE(line.Amount); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reconciliation compares the totals of an invoice with the sums of its lines
+// when each line is rounded separately.
+type Reconciliation struct {
+	Rounding RoundingPolicy  `json:"rounding"` // The rounding policy of the invoice.
+	Net      TotalDifference `json:"net"`      // The difference in the net total.
+	Tax      TotalDifference `json:"tax"`      // The difference in the total tax.
+	Gross    TotalDifference `json:"gross"`    // The difference in the gross total.
+	Discount TotalDifference `json:"discount"` // The difference in the total discount.
+	Taxes    []TaxDifference `json:"taxes"`    // The difference in each tax.
+}
+
+// Balanced returns true if the invoice totals equal the sums of the lines.
+func (r Reconciliation) Balanced() bool {
+	if !r.Net.Difference.IsZero() || !r.Tax.Difference.IsZero() || !r.Gross.Difference.IsZero() || !r.Discount.Difference.IsZero() {
+		return false
+	}
+	for _, t := range r.Taxes {
+		if !t.Difference.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// TotalDifference is the difference between an invoice total and the sum of
+// the lines.
+type TotalDifference struct {
+	Invoice    mongo.Money `json:"invoice"`    // The invoice total.
+	Lines      mongo.Money `json:"lines"`      // The sum of the lines.
+	Difference mongo.Money `json:"difference"` // The invoice total minus the sum of the lines.
+}
+
+// TaxDifference is the difference between the invoice total of a tax and the
+// sum of the tax on each line.
+type TaxDifference struct {
+	Tax        mongo.Tax   `json:"tax"`        // The definition of the tax.
+	Invoice    mongo.Money `json:"invoice"`    // The invoice total of the tax.
+	Lines      mongo.Money `json:"lines"`      // The sum of the tax on each line.
+	Difference mongo.Money `json:"difference"` // The invoice total minus the sum of the lines.
+}
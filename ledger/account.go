@@ -0,0 +1,61 @@
+package ledger
+
+// AccountType classifies an account and determines its normal balance.
+type AccountType int
+
+const (
+	// Asset accounts hold resources owned, e.g. cash or receivables. They
+	// normally have a debit balance.
+	Asset AccountType = iota
+
+	// Liability accounts hold amounts owed, e.g. customer wallets or payables.
+	// They normally have a credit balance.
+	Liability
+
+	// Equity accounts hold the owners' interest. They normally have a credit
+	// balance.
+	Equity
+
+	// Income accounts hold revenue, e.g. fees earned. They normally have a
+	// credit balance.
+	Income
+
+	// Expense accounts hold costs incurred. They normally have a debit
+	// balance.
+	Expense
+)
+
+// String is an implementation of fmt.Stringer.
+func (t AccountType) String() string {
+	switch t {
+	case Asset:
+		return "Asset"
+	case Liability:
+		return "Liability"
+	case Equity:
+		return "Equity"
+	case Income:
+		return "Income"
+	case Expense:
+		return "Expense"
+	}
+	return "Unknown"
+}
+
+// normalDebit returns true if accounts of this type normally have a debit
+// balance.
+func (t AccountType) normalDebit() bool {
+	return t == Asset || t == Expense
+}
+
+// valid returns true if the account type is recognised.
+func (t AccountType) valid() bool {
+	return t >= Asset && t <= Expense
+}
+
+// Account is a named account that postings are made to.
+type Account struct {
+	ID   string      // The unique identifier of the account.
+	Name string      // The name of the account.
+	Type AccountType // The type of the account.
+}
@@ -0,0 +1,295 @@
+// Package ledger implements a double entry bookkeeping ledger using mongo's
+// lossless money arithmetic.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nomad-software/mongo"
+)
+
+var (
+	// ErrUnbalanced is returned when the debits and credits of a transaction
+	// are not equal in every currency.
+	ErrUnbalanced = errors.New("transaction is unbalanced")
+
+	// ErrAccountNotFound is returned when an account doesn't exist.
+	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrAccountExists is returned when opening an account that already
+	// exists.
+	ErrAccountExists = errors.New("account already exists")
+
+	// ErrTransactionNotFound is returned when a transaction doesn't exist.
+	ErrTransactionNotFound = errors.New("transaction not found")
+
+	// ErrTransactionExists is returned when committing a transaction with an
+	// ID that has already been used.
+	ErrTransactionExists = errors.New("transaction already exists")
+
+	// ErrAlreadyReversed is returned when reversing a transaction that has
+	// already been reversed.
+	ErrAlreadyReversed = errors.New("transaction already reversed")
+)
+
+// Ledger is a double entry bookkeeping ledger. Transactions are only stored if
+// their debits and credits balance in every currency. It's safe for
+// concurrent use.
+type Ledger struct {
+	mu    sync.Mutex
+	store Store
+}
+
+// New constructs a new ledger using the passed store.
+func New(store Store) *Ledger {
+	return &Ledger{store: store}
+}
+
+// OpenAccount adds a new account to the ledger.
+func (l *Ledger) OpenAccount(a Account) error {
+	if a.ID == "" {
+		return fmt.Errorf("failed to open account, no ID specified")
+	}
+	if !a.Type.valid() {
+		return fmt.Errorf("failed to open account '%s', invalid account type %d", a.ID, a.Type)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.store.Account(a.ID); err == nil {
+		return fmt.Errorf("failed to open account '%s', %w", a.ID, ErrAccountExists)
+	}
+	return l.store.CreateAccount(a)
+}
+
+// Account returns the account with the passed ID.
+func (l *Ledger) Account(id string) (Account, error) {
+	return l.store.Account(id)
+}
+
+// Commit validates a transaction and stores it. The transaction must balance
+// in every currency and only post to open accounts.
+func (l *Ledger) Commit(t Transaction) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.commit(t)
+}
+
+// Reverse commits a new transaction that reverses the effect of an existing
+// one by swapping its debits and credits. A transaction can only be reversed
+// once.
+func (l *Ledger) Reverse(id string, reversalID string, date time.Time) (Transaction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, err := l.store.Transaction(id)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("failed to reverse transaction '%s', %w", id, err)
+	}
+	r := t.Reverse(reversalID, date)
+	if err := r.Validate(); err != nil {
+		return Transaction{}, err
+	}
+	if err := l.commit(r); err != nil {
+		return Transaction{}, err
+	}
+	return r, nil
+}
+
+// commit stores a valid transaction. The caller must hold the lock.
+func (l *Ledger) commit(t Transaction) error {
+	if _, err := l.store.Transaction(t.ID); err == nil {
+		return fmt.Errorf("failed to commit transaction '%s', %w", t.ID, ErrTransactionExists)
+	}
+	for _, p := range t.Postings {
+		if _, err := l.store.Account(p.Account); err != nil {
+			return fmt.Errorf("failed to commit transaction '%s', %w", t.ID, err)
+		}
+	}
+
+	if t.Reverses != "" {
+		if _, err := l.store.Transaction(t.Reverses); err != nil {
+			return fmt.Errorf("failed to commit transaction '%s', %w", t.ID, err)
+		}
+		all, err := l.store.Transactions()
+		if err != nil {
+			return err
+		}
+		for _, existing := range all {
+			if existing.Reverses == t.Reverses {
+				return fmt.Errorf("failed to commit transaction '%s', '%s' %w by '%s'", t.ID, t.Reverses, ErrAlreadyReversed, existing.ID)
+			}
+		}
+	}
+
+	return l.store.Append(t.clone())
+}
+
+// Transactions returns all committed transactions in the order they were
+// committed.
+func (l *Ledger) Transactions() ([]Transaction, error) {
+	return l.store.Transactions()
+}
+
+// Balance returns the balance of an account in each currency. Balances are
+// positive when they're on the account's normal side, i.e. debit balances for
+// assets and expenses and credit balances for liabilities, equity and income.
+func (l *Ledger) Balance(id string) (mongo.Bag, error) {
+	entries, err := l.Statement(id)
+	if err != nil {
+		return mongo.Bag{}, err
+	}
+	var balance mongo.Bag
+	for _, e := range entries {
+		if balance, err = balance.AddE(e.signed()); err != nil {
+			return mongo.Bag{}, fmt.Errorf("failed to balance account '%s', %w", id, err)
+		}
+	}
+	return balance, nil
+}
+
+// Statement returns every posting to an account with a running balance in the
+// posting's currency, ordered by transaction date. Transactions with the same
+// date are kept in the order they were committed.
+func (l *Ledger) Statement(id string) ([]Entry, error) {
+	a, err := l.store.Account(id)
+	if err != nil {
+		return nil, err
+	}
+	all, err := l.store.Transactions()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Date.Before(all[j].Date)
+	})
+
+	var entries []Entry
+	var balance mongo.Bag
+	for _, t := range all {
+		for _, p := range t.Postings {
+			if p.Account != id {
+				continue
+			}
+			e := Entry{
+				Transaction: t.ID,
+				Date:        t.Date,
+				Description: t.Description,
+				Posting:     p,
+				normalDebit: a.Type.normalDebit(),
+			}
+			if balance, err = balance.AddE(e.signed()); err != nil {
+				return nil, fmt.Errorf("failed to create statement for account '%s', %w", id, err)
+			}
+			e.Balance, _ = balance.Get(p.Amount.IsoCode())
+			if e.Balance.IsoCode() == "" {
+				e.Balance = p.Amount.Clone(0)
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// TrialBalance returns the net debit or credit balance of every account. If
+// every transaction was committed through the ledger the totals of the debit
+// and credit columns are equal in every currency.
+func (l *Ledger) TrialBalance() (TrialBalance, error) {
+	accounts, err := l.store.Accounts()
+	if err != nil {
+		return TrialBalance{}, err
+	}
+	all, err := l.store.Transactions()
+	if err != nil {
+		return TrialBalance{}, err
+	}
+
+	net := make(map[string]mongo.Bag, len(accounts))
+	for _, t := range all {
+		for _, p := range t.Postings {
+			if p.Direction == Debit {
+				net[p.Account], err = net[p.Account].AddE(p.Amount)
+			} else {
+				net[p.Account], err = net[p.Account].SubE(p.Amount)
+			}
+			if err != nil {
+				return TrialBalance{}, fmt.Errorf("failed to create trial balance, %w", err)
+			}
+		}
+	}
+
+	var tb TrialBalance
+	for _, a := range accounts {
+		line := TrialBalanceLine{Account: a}
+		for _, m := range net[a.ID].Values() {
+			if m.IsNeg() {
+				if m, err = m.AbsE(); err == nil {
+					line.Credit, err = line.Credit.AddE(m)
+				}
+			} else {
+				line.Debit, err = line.Debit.AddE(m)
+			}
+			if err != nil {
+				return TrialBalance{}, fmt.Errorf("failed to create trial balance, %w", err)
+			}
+		}
+		tb.Lines = append(tb.Lines, line)
+		if tb.Debits, err = tb.Debits.AddBagE(line.Debit); err != nil {
+			return TrialBalance{}, fmt.Errorf("failed to create trial balance, %w", err)
+		}
+		if tb.Credits, err = tb.Credits.AddBagE(line.Credit); err != nil {
+			return TrialBalance{}, fmt.Errorf("failed to create trial balance, %w", err)
+		}
+	}
+	return tb, nil
+}
+
+// Entry is a posting to an account with the running balance of the account in
+// the posting's currency.
+type Entry struct {
+	Transaction string      // The ID of the transaction.
+	Date        time.Time   // The date of the transaction.
+	Description string      // The description of the transaction.
+	Posting     Posting     // The posting to the account.
+	Balance     mongo.Money // The balance of the account after the posting.
+	normalDebit bool        // Whether the account normally has a debit balance.
+}
+
+// signed returns the posting amount signed according to the account's normal
+// balance.
+func (e Entry) signed() mongo.Money {
+	if (e.Posting.Direction == Debit) == e.normalDebit {
+		return e.Posting.Amount
+	}
+	return e.Posting.Amount.FlipSign()
+}
+
+// TrialBalance is a report of the net balance of every account.
+type TrialBalance struct {
+	Lines   []TrialBalanceLine // The net balance of each account, sorted by account ID.
+	Debits  mongo.Bag          // The total of the debit column.
+	Credits mongo.Bag          // The total of the credit column.
+}
+
+// Balanced returns true if the debit and credit columns are equal in every
+// currency.
+func (tb TrialBalance) Balanced() bool {
+	diff, err := tb.Debits.SubBagE(tb.Credits)
+	return err == nil && diff.IsZero()
+}
+
+// TrialBalanceLine is the net balance of an account, which is either on the
+// debit or credit side for each currency.
+type TrialBalanceLine struct {
+	Account Account   // The account.
+	Debit   mongo.Bag // The net debit balance.
+	Credit  mongo.Bag // The net credit balance.
+}
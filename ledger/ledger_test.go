@@ -0,0 +1,260 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nomad-software/mongo"
+)
+
+func newTestLedger(t *testing.T) *Ledger {
+	l := New(NewMemoryStore())
+	accounts := []Account{
+		{ID: "cash", Name: "Cash", Type: Asset},
+		{ID: "wallet", Name: "Customer wallet", Type: Liability},
+		{ID: "fees", Name: "Fees", Type: Income},
+		{ID: "costs", Name: "Processing costs", Type: Expense},
+	}
+	for _, a := range accounts {
+		if err := l.OpenAccount(a); err != nil {
+			t.Fatalf("OpenAccount failed: %s", err)
+		}
+	}
+	return l
+}
+
+func day(d int) time.Time {
+	return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestLedgerOpenAccount(t *testing.T) {
+	l := newTestLedger(t)
+
+	if err := l.OpenAccount(Account{ID: "cash", Type: Asset}); !errors.Is(err, ErrAccountExists) {
+		t.Errorf("OpenAccount failed to return ErrAccountExists, returned %v", err)
+	}
+	if err := l.OpenAccount(Account{Type: Asset}); err == nil {
+		t.Errorf("OpenAccount failed to error on an empty ID")
+	}
+	if err := l.OpenAccount(Account{ID: "x", Type: 10}); err == nil {
+		t.Errorf("OpenAccount failed to error on an invalid type")
+	}
+
+	a, err := l.Account("wallet")
+	if err != nil || a.Type != Liability || a.Type.String() != "Liability" {
+		t.Errorf("Account returned %+v, %v", a, err)
+	}
+	if _, err := l.Account("x"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Account failed to return ErrAccountNotFound, returned %v", err)
+	}
+}
+
+func TestLedgerCommit(t *testing.T) {
+	l := newTestLedger(t)
+
+	deposit := Transaction{
+		ID:       "t1",
+		Date:     day(1),
+		Postings: []Posting{DebitOf("cash", gbp(10000)), CreditOf("wallet", gbp(10000))},
+	}
+	if err := l.Commit(deposit); err != nil {
+		t.Fatalf("Commit failed: %s", err)
+	}
+	if err := l.Commit(deposit); !errors.Is(err, ErrTransactionExists) {
+		t.Errorf("Commit failed to return ErrTransactionExists, returned %v", err)
+	}
+
+	unbalanced := Transaction{
+		ID:       "t2",
+		Postings: []Posting{DebitOf("cash", gbp(100)), CreditOf("wallet", gbp(99))},
+	}
+	if err := l.Commit(unbalanced); !errors.Is(err, ErrUnbalanced) {
+		t.Errorf("Commit failed to return ErrUnbalanced, returned %v", err)
+	}
+
+	unknown := Transaction{
+		ID:       "t3",
+		Postings: []Posting{DebitOf("cash", gbp(100)), CreditOf("nowhere", gbp(100))},
+	}
+	if err := l.Commit(unknown); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Commit failed to return ErrAccountNotFound, returned %v", err)
+	}
+
+	all, _ := l.Transactions()
+	if len(all) != 1 {
+		t.Errorf("Transactions returned %d transactions, expected 1", len(all))
+	}
+
+	// The committed transaction can't be modified by the caller.
+	deposit.Postings[0].Account = "fees"
+	all, _ = l.Transactions()
+	if all[0].Postings[0].Account != "cash" {
+		t.Errorf("Commit stored the caller's postings")
+	}
+}
+
+func TestLedgerBalances(t *testing.T) {
+	l := newTestLedger(t)
+
+	txs := []Transaction{
+		{ID: "t1", Date: day(1), Postings: []Posting{DebitOf("cash", gbp(10000)), CreditOf("wallet", gbp(10000))}},
+		{ID: "t3", Date: day(3), Postings: []Posting{DebitOf("wallet", gbp(2500)), CreditOf("cash", gbp(2450)), CreditOf("fees", gbp(50))}},
+		{ID: "t2", Date: day(2), Postings: []Posting{DebitOf("cash", eur(500)), CreditOf("wallet", eur(500))}},
+		{ID: "t4", Date: day(4), Postings: []Posting{DebitOf("costs", gbp(20)), CreditOf("cash", gbp(20))}},
+	}
+	for _, tx := range txs {
+		if err := l.Commit(tx); err != nil {
+			t.Fatalf("Commit failed: %s", err)
+		}
+	}
+
+	balances := map[string]string{
+		"cash":   "€5.00, £75.30",
+		"wallet": "€5.00, £75.00",
+		"fees":   "£0.50",
+		"costs":  "£0.20",
+	}
+	for id, expected := range balances {
+		b, err := l.Balance(id)
+		if err != nil {
+			t.Errorf("Balance(%s) failed: %s", id, err)
+		}
+		if b.String() != expected {
+			t.Errorf("Balance(%s) returned '%s', expected '%s'", id, b, expected)
+		}
+	}
+
+	entries, err := l.Statement("cash")
+	if err != nil {
+		t.Fatalf("Statement failed: %s", err)
+	}
+	expected := []string{"t1 £100.00", "t2 €5.00", "t3 £75.50", "t4 £75.30"}
+	if len(entries) != len(expected) {
+		t.Fatalf("Statement returned %d entries, expected %d", len(entries), len(expected))
+	}
+	for i, e := range entries {
+		if str := fmt.Sprintf("%s %s", e.Transaction, e.Balance); str != expected[i] {
+			t.Errorf("Statement entry %d is '%s', expected '%s'", i, str, expected[i])
+		}
+	}
+
+	if _, err := l.Balance("x"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Balance failed to return ErrAccountNotFound, returned %v", err)
+	}
+}
+
+func TestLedgerTrialBalance(t *testing.T) {
+	l := newTestLedger(t)
+	l.Commit(Transaction{ID: "t1", Postings: []Posting{DebitOf("cash", gbp(10000)), CreditOf("wallet", gbp(10000))}})
+	l.Commit(Transaction{ID: "t2", Postings: []Posting{DebitOf("wallet", gbp(2500)), CreditOf("cash", gbp(2450)), CreditOf("fees", gbp(50))}})
+	l.Commit(Transaction{ID: "t3", Postings: []Posting{DebitOf("cash", eur(500)), CreditOf("wallet", eur(500))}})
+
+	tb, err := l.TrialBalance()
+	if err != nil {
+		t.Fatalf("TrialBalance failed: %s", err)
+	}
+	if !tb.Balanced() {
+		t.Errorf("TrialBalance is not balanced")
+	}
+	if tb.Debits.String() != "€5.00, £75.50" || tb.Credits.String() != "€5.00, £75.50" {
+		t.Errorf("TrialBalance totals are %s and %s", tb.Debits, tb.Credits)
+	}
+
+	lines := map[string][2]string{
+		"cash":   {"€5.00, £75.50", ""},
+		"costs":  {"", ""},
+		"fees":   {"", "£0.50"},
+		"wallet": {"", "€5.00, £75.00"},
+	}
+	if len(tb.Lines) != len(lines) || tb.Lines[0].Account.ID != "cash" {
+		t.Fatalf("TrialBalance returned %d lines", len(tb.Lines))
+	}
+	for _, line := range tb.Lines {
+		expected := lines[line.Account.ID]
+		if line.Debit.String() != expected[0] || line.Credit.String() != expected[1] {
+			t.Errorf("TrialBalance line %s is %s / %s", line.Account.ID, line.Debit, line.Credit)
+		}
+	}
+}
+
+func TestLedgerOverflow(t *testing.T) {
+	l := newTestLedger(t)
+	l.Commit(Transaction{ID: "t1", Postings: []Posting{DebitOf("cash", gbp(math.MaxInt64)), CreditOf("wallet", gbp(math.MaxInt64))}})
+	l.Commit(Transaction{ID: "t2", Postings: []Posting{DebitOf("cash", gbp(2)), CreditOf("wallet", gbp(2))}})
+
+	if _, err := l.Balance("cash"); !errors.Is(err, mongo.ErrOverflow) {
+		t.Errorf("Balance failed to return ErrOverflow, returned %v", err)
+	}
+	if _, err := l.Statement("wallet"); !errors.Is(err, mongo.ErrOverflow) {
+		t.Errorf("Statement failed to return ErrOverflow, returned %v", err)
+	}
+	if _, err := l.TrialBalance(); !errors.Is(err, mongo.ErrOverflow) {
+		t.Errorf("TrialBalance failed to return ErrOverflow, returned %v", err)
+	}
+}
+
+func TestLedgerReverse(t *testing.T) {
+	l := newTestLedger(t)
+	l.Commit(Transaction{ID: "t1", Date: day(1), Postings: []Posting{DebitOf("cash", gbp(10000)), CreditOf("wallet", gbp(10000))}})
+
+	r, err := l.Reverse("t1", "r1", day(2))
+	if err != nil {
+		t.Fatalf("Reverse failed: %s", err)
+	}
+	if r.Reverses != "t1" {
+		t.Errorf("Reverse returned %+v", r)
+	}
+
+	b, _ := l.Balance("cash")
+	if !b.IsZero() {
+		t.Errorf("Balance after reversal is %s", b)
+	}
+
+	entries, _ := l.Statement("cash")
+	if len(entries) != 2 || entries[1].Balance.String() != "£0.00" {
+		t.Errorf("Statement after reversal is %+v", entries)
+	}
+
+	if _, err := l.Reverse("t1", "r2", day(3)); !errors.Is(err, ErrAlreadyReversed) {
+		t.Errorf("Reverse failed to return ErrAlreadyReversed, returned %v", err)
+	}
+	if _, err := l.Reverse("x", "r3", day(3)); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("Reverse failed to return ErrTransactionNotFound, returned %v", err)
+	}
+
+	manual := Transaction{ID: "r4", Reverses: "t1", Postings: []Posting{DebitOf("wallet", gbp(1)), CreditOf("cash", gbp(1))}}
+	if err := l.Commit(manual); !errors.Is(err, ErrAlreadyReversed) {
+		t.Errorf("Commit failed to return ErrAlreadyReversed, returned %v", err)
+	}
+}
+
+func TestLedgerConcurrency(t *testing.T) {
+	l := newTestLedger(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tx := Transaction{
+				ID:       fmt.Sprintf("t%d", i),
+				Postings: []Posting{DebitOf("cash", gbp(100)), CreditOf("wallet", gbp(100))},
+			}
+			if err := l.Commit(tx); err != nil {
+				t.Errorf("Commit failed: %s", err)
+			}
+			l.Balance("cash")
+			l.TrialBalance()
+		}(i)
+	}
+	wg.Wait()
+
+	b, _ := l.Balance("cash")
+	if b.String() != "£50.00" {
+		t.Errorf("Balance after concurrent commits is %s", b)
+	}
+}
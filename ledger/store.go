@@ -0,0 +1,119 @@
+package ledger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Store is implemented by anything that can persist accounts and committed
+// transactions. The ledger validates everything before it's stored.
+type Store interface {
+	// CreateAccount stores a new account.
+	CreateAccount(a Account) error
+
+	// Account returns the account with the passed ID or an error wrapping
+	// ErrAccountNotFound.
+	Account(id string) (Account, error)
+
+	// Accounts returns all accounts sorted by ID.
+	Accounts() ([]Account, error)
+
+	// Append stores a committed transaction.
+	Append(t Transaction) error
+
+	// Transaction returns the transaction with the passed ID or an error
+	// wrapping ErrTransactionNotFound.
+	Transaction(id string) (Transaction, error)
+
+	// Transactions returns all transactions in the order they were appended.
+	Transactions() ([]Transaction, error)
+}
+
+// MemoryStore is a store that holds accounts and transactions in memory. It's
+// safe for concurrent use.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	accounts     map[string]Account
+	transactions []Transaction
+	index        map[string]int
+}
+
+// NewMemoryStore constructs a new empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		accounts: make(map[string]Account),
+		index:    make(map[string]int),
+	}
+}
+
+// CreateAccount is an implementation of Store.
+func (s *MemoryStore) CreateAccount(a Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.accounts[a.ID]; ok {
+		return fmt.Errorf("failed to create account '%s', %w", a.ID, ErrAccountExists)
+	}
+	s.accounts[a.ID] = a
+	return nil
+}
+
+// Account is an implementation of Store.
+func (s *MemoryStore) Account(id string) (Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, ok := s.accounts[id]
+	if !ok {
+		return Account{}, fmt.Errorf("'%s' %w", id, ErrAccountNotFound)
+	}
+	return a, nil
+}
+
+// Accounts is an implementation of Store.
+func (s *MemoryStore) Accounts() ([]Account, error) {
+	s.mu.RLock()
+	accounts := make([]Account, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		accounts = append(accounts, a)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID < accounts[j].ID
+	})
+	return accounts, nil
+}
+
+// Append is an implementation of Store.
+func (s *MemoryStore) Append(t Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.index[t.ID]; ok {
+		return fmt.Errorf("failed to append transaction '%s', %w", t.ID, ErrTransactionExists)
+	}
+	s.index[t.ID] = len(s.transactions)
+	s.transactions = append(s.transactions, t.clone())
+	return nil
+}
+
+// Transaction is an implementation of Store.
+func (s *MemoryStore) Transaction(id string) (Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, ok := s.index[id]
+	if !ok {
+		return Transaction{}, fmt.Errorf("'%s' %w", id, ErrTransactionNotFound)
+	}
+	return s.transactions[i].clone(), nil
+}
+
+// Transactions is an implementation of Store.
+func (s *MemoryStore) Transactions() ([]Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	all := make([]Transaction, len(s.transactions))
+	for i, t := range s.transactions {
+		all[i] = t.clone()
+	}
+	return all, nil
+}
@@ -0,0 +1,126 @@
+package ledger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nomad-software/mongo"
+)
+
+// Direction specifies whether a posting is a debit or a credit.
+type Direction int
+
+const (
+	// Debit increases asset and expense accounts and decreases liability,
+	// equity and income accounts.
+	Debit Direction = iota
+
+	// Credit decreases asset and expense accounts and increases liability,
+	// equity and income accounts.
+	Credit
+)
+
+// String is an implementation of fmt.Stringer.
+func (d Direction) String() string {
+	if d == Credit {
+		return "Credit"
+	}
+	return "Debit"
+}
+
+// Posting is a single debit or credit of money to an account.
+type Posting struct {
+	Account   string      // The ID of the account.
+	Direction Direction   // Whether the posting is a debit or a credit.
+	Amount    mongo.Money // The amount posted, which must be greater than zero.
+}
+
+// DebitOf is a helper function that returns a debit posting.
+func DebitOf(account string, amount mongo.Money) Posting {
+	return Posting{Account: account, Direction: Debit, Amount: amount}
+}
+
+// CreditOf is a helper function that returns a credit posting.
+func CreditOf(account string, amount mongo.Money) Posting {
+	return Posting{Account: account, Direction: Credit, Amount: amount}
+}
+
+// Transaction is a journal entry made of postings whose debits and credits
+// must balance in every currency.
+type Transaction struct {
+	ID          string    // The unique identifier of the transaction.
+	Date        time.Time // The date of the transaction.
+	Description string    // The description of the transaction.
+	Postings    []Posting // The debits and credits.
+	Reverses    string    // The ID of the transaction this reverses, if any.
+}
+
+// Validate checks the transaction has at least two postings, that every
+// amount is greater than zero and that the debits and credits balance in every
+// currency. An error wrapping ErrUnbalanced is returned if they don't.
+func (t Transaction) Validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("invalid transaction, no ID specified")
+	}
+	if len(t.Postings) < 2 {
+		return fmt.Errorf("invalid transaction '%s', at least two postings are required", t.ID)
+	}
+
+	var debits, credits mongo.Bag
+	for i, p := range t.Postings {
+		if p.Account == "" {
+			return fmt.Errorf("invalid transaction '%s', posting %d has no account", t.ID, i)
+		}
+		if p.Amount.IsoCode() == "" || p.Amount.IsNeg() || p.Amount.IsZero() {
+			return fmt.Errorf("invalid transaction '%s', posting %d amount must be greater than zero", t.ID, i)
+		}
+		var err error
+		switch p.Direction {
+		case Debit:
+			debits, err = debits.AddE(p.Amount)
+		case Credit:
+			credits, err = credits.AddE(p.Amount)
+		default:
+			return fmt.Errorf("invalid transaction '%s', posting %d has an invalid direction %d", t.ID, i, p.Direction)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid transaction '%s', %w", t.ID, err)
+		}
+	}
+
+	diff, err := debits.SubBagE(credits)
+	if err != nil {
+		return fmt.Errorf("invalid transaction '%s', %w", t.ID, err)
+	}
+	if !diff.IsZero() {
+		return fmt.Errorf("invalid transaction '%s', debits %s and credits %s differ by %s, %w", t.ID, debits, credits, diff, ErrUnbalanced)
+	}
+	return nil
+}
+
+// Reverse returns a new transaction that reverses the effect of this one by
+// swapping its debits and credits.
+func (t Transaction) Reverse(id string, date time.Time) Transaction {
+	r := Transaction{
+		ID:          id,
+		Date:        date,
+		Description: fmt.Sprintf("Reversal of %s", t.ID),
+		Postings:    make([]Posting, len(t.Postings)),
+		Reverses:    t.ID,
+	}
+	for i, p := range t.Postings {
+		if p.Direction == Debit {
+			p.Direction = Credit
+		} else {
+			p.Direction = Debit
+		}
+		r.Postings[i] = p
+	}
+	return r
+}
+
+// clone returns a copy of the transaction that doesn't share its postings.
+func (t Transaction) clone() Transaction {
+	t.Postings = append([]Posting{}, t.Postings...)
+	return t
+}
@@ -0,0 +1,106 @@
+package ledger
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nomad-software/mongo"
+)
+
+func gbp(value int64) mongo.Money {
+	m, _ := mongo.MoneyGBP(value)
+	return m
+}
+
+func eur(value int64) mongo.Money {
+	m, _ := mongo.MoneyEUR(value)
+	return m
+}
+
+func TestTransactionValidate(t *testing.T) {
+	valid := Transaction{
+		ID: "t1",
+		Postings: []Posting{
+			DebitOf("cash", gbp(1000)),
+			DebitOf("cash-eur", eur(500)),
+			CreditOf("wallet", gbp(600)),
+			CreditOf("fees", gbp(400)),
+			CreditOf("wallet-eur", eur(500)),
+		},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate failed: %s", err)
+	}
+
+	unbalanced := Transaction{
+		ID:       "t2",
+		Postings: []Posting{DebitOf("cash", gbp(1000)), CreditOf("wallet", gbp(999))},
+	}
+	if err := unbalanced.Validate(); !errors.Is(err, ErrUnbalanced) {
+		t.Errorf("Validate failed to return ErrUnbalanced, returned %v", err)
+	}
+
+	// Balanced in total but not per currency.
+	mixed := Transaction{
+		ID:       "t3",
+		Postings: []Posting{DebitOf("cash", gbp(1000)), CreditOf("wallet", eur(1000))},
+	}
+	if err := mixed.Validate(); !errors.Is(err, ErrUnbalanced) {
+		t.Errorf("Validate failed to return ErrUnbalanced, returned %v", err)
+	}
+
+	invalid := []Transaction{
+		{Postings: []Posting{DebitOf("cash", gbp(1)), CreditOf("wallet", gbp(1))}},
+		{ID: "t", Postings: []Posting{DebitOf("cash", gbp(1))}},
+		{ID: "t", Postings: []Posting{DebitOf("", gbp(1)), CreditOf("wallet", gbp(1))}},
+		{ID: "t", Postings: []Posting{DebitOf("cash", gbp(0)), CreditOf("wallet", gbp(0))}},
+		{ID: "t", Postings: []Posting{DebitOf("cash", gbp(-1)), CreditOf("wallet", gbp(-1))}},
+		{ID: "t", Postings: []Posting{DebitOf("cash", mongo.Money{}), CreditOf("wallet", mongo.Money{})}},
+		{ID: "t", Postings: []Posting{{Account: "cash", Direction: 5, Amount: gbp(1)}, CreditOf("wallet", gbp(1))}},
+	}
+	for _, tx := range invalid {
+		if err := tx.Validate(); err == nil {
+			t.Errorf("Validate failed to error on %+v", tx)
+		}
+	}
+
+	// The sums wrap around to the same value if they're unchecked.
+	overflow := Transaction{
+		ID: "t4",
+		Postings: []Posting{
+			DebitOf("cash", gbp(math.MaxInt64)),
+			DebitOf("cash", gbp(2)),
+			CreditOf("wallet", gbp(math.MaxInt64)),
+			CreditOf("wallet", gbp(math.MaxInt64)),
+			CreditOf("wallet", gbp(math.MaxInt64)),
+			CreditOf("wallet", gbp(4)),
+		},
+	}
+	if err := overflow.Validate(); !errors.Is(err, mongo.ErrOverflow) {
+		t.Errorf("Validate failed to return ErrOverflow, returned %v", err)
+	}
+}
+
+func TestTransactionReverse(t *testing.T) {
+	tx := Transaction{
+		ID:       "t1",
+		Postings: []Posting{DebitOf("cash", gbp(1000)), CreditOf("wallet", gbp(1000))},
+	}
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	r := tx.Reverse("t2", date)
+
+	if r.ID != "t2" || r.Reverses != "t1" || !r.Date.Equal(date) || r.Description != "Reversal of t1" {
+		t.Errorf("Reverse returned %+v", r)
+	}
+	if r.Postings[0].Direction != Credit || r.Postings[1].Direction != Debit {
+		t.Errorf("Reverse failed to swap debits and credits")
+	}
+	if tx.Postings[0].Direction != Debit {
+		t.Errorf("Reverse modified the original transaction")
+	}
+	if err := r.Validate(); err != nil {
+		t.Errorf("Validate failed: %s", err)
+	}
+}
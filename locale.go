@@ -0,0 +1,298 @@
+package mongo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// NegativeStyle specifies how negative monetary values are presented.
+type NegativeStyle int
+
+const (
+	// NegativeSymbolMinus places a minus sign directly before the number,
+	// after any leading currency symbol, e.g. "£-10.55", "€ -1.234,56" or
+	// "-10.55 CHF". This is the default.
+	NegativeSymbolMinus NegativeStyle = iota
+
+	// NegativeLeadingMinus places a minus sign before everything else, e.g.
+	// "-£10.55" or "-1.234,56 €".
+	NegativeLeadingMinus
+
+	// NegativeParentheses encloses negative values in parentheses as used in
+	// accounting, e.g. "(£10.55)" or "(1.234,56 €)".
+	NegativeParentheses
+
+	// NegativeTrailingMinus places a minus sign directly after the number,
+	// e.g. "£10.55-" or "1.234,56- €".
+	NegativeTrailingMinus
+
+	// NegativeCRDR appends "CR" to negative values and "DR" to positive
+	// values, e.g. "£10.55 CR" and "£10.55 DR". Zero has no suffix.
+	NegativeCRDR
+)
+
+// apply presents an unsigned number using the negative style. sign is the
+// sign of the value and wrap adds the currency symbol to a number.
+func (s NegativeStyle) apply(num string, sign int, wrap func(string) string) string {
+	if sign < 0 {
+		switch s {
+		case NegativeLeadingMinus:
+			return "-" + wrap(num)
+		case NegativeParentheses:
+			return "(" + wrap(num) + ")"
+		case NegativeTrailingMinus:
+			return wrap(num + "-")
+		case NegativeCRDR:
+			return wrap(num) + " CR"
+		default:
+			return wrap("-" + num)
+		}
+	}
+	if sign > 0 && s == NegativeCRDR {
+		return wrap(num) + " DR"
+	}
+	return wrap(num)
+}
+
+// valid returns true if the negative style is recognised.
+func (s NegativeStyle) valid() bool {
+	return s >= NegativeSymbolMinus && s <= NegativeCRDR
+}
+
+// Locale defines how monetary values are formatted for a language and region.
+// It's independent of the currency, so euros can be formatted as "€1,234.56"
+// for English readers or "1.234,56 €" for German readers.
+type Locale struct {
+	Tag         string        // The BCP 47 language tag, e.g. "de-DE".
+	DecimalSep  string        // The decimal mark.
+	GroupSep    string        // The digit grouping separator.
+	Grouping    []int         // The digit group sizes from the right, the last size repeats, e.g. {3} or {3, 2}.
+	SymbolFirst bool          // Whether the currency symbol is placed before the number.
+	SymbolSpace bool          // Whether a space separates the currency symbol and the number.
+	Negative    NegativeStyle // How negative values are presented.
+}
+
+// builtinLocales contain a map of all locales registered by default.
+var builtinLocales = map[string]Locale{
+	"en":    {Tag: "en", DecimalSep: ".", GroupSep: ",", Grouping: []int{3}, SymbolFirst: true, Negative: NegativeLeadingMinus},
+	"en-GB": {Tag: "en-GB", DecimalSep: ".", GroupSep: ",", Grouping: []int{3}, SymbolFirst: true, Negative: NegativeLeadingMinus},
+	"en-US": {Tag: "en-US", DecimalSep: ".", GroupSep: ",", Grouping: []int{3}, SymbolFirst: true, Negative: NegativeLeadingMinus},
+	"en-IN": {Tag: "en-IN", DecimalSep: ".", GroupSep: ",", Grouping: []int{3, 2}, SymbolFirst: true, Negative: NegativeLeadingMinus},
+	"hi-IN": {Tag: "hi-IN", DecimalSep: ".", GroupSep: ",", Grouping: []int{3, 2}, SymbolFirst: true, Negative: NegativeLeadingMinus},
+	"de":    {Tag: "de", DecimalSep: ",", GroupSep: ".", Grouping: []int{3}, SymbolSpace: true},
+	"de-DE": {Tag: "de-DE", DecimalSep: ",", GroupSep: ".", Grouping: []int{3}, SymbolSpace: true},
+	"de-AT": {Tag: "de-AT", DecimalSep: ",", GroupSep: ".", Grouping: []int{3}, SymbolFirst: true, SymbolSpace: true},
+	"de-CH": {Tag: "de-CH", DecimalSep: ".", GroupSep: "'", Grouping: []int{3}, SymbolFirst: true, SymbolSpace: true},
+	"fr":    {Tag: "fr", DecimalSep: ",", GroupSep: " ", Grouping: []int{3}, SymbolSpace: true},
+	"fr-FR": {Tag: "fr-FR", DecimalSep: ",", GroupSep: " ", Grouping: []int{3}, SymbolSpace: true},
+	"fr-CH": {Tag: "fr-CH", DecimalSep: ",", GroupSep: " ", Grouping: []int{3}, SymbolSpace: true},
+	"es":    {Tag: "es", DecimalSep: ",", GroupSep: ".", Grouping: []int{3}, SymbolSpace: true},
+	"it":    {Tag: "it", DecimalSep: ",", GroupSep: ".", Grouping: []int{3}, SymbolSpace: true},
+	"nl":    {Tag: "nl", DecimalSep: ",", GroupSep: ".", Grouping: []int{3}, SymbolFirst: true, SymbolSpace: true},
+	"pt-BR": {Tag: "pt-BR", DecimalSep: ",", GroupSep: ".", Grouping: []int{3}, SymbolFirst: true, SymbolSpace: true, Negative: NegativeLeadingMinus},
+	"pl":    {Tag: "pl", DecimalSep: ",", GroupSep: " ", Grouping: []int{3}, SymbolSpace: true},
+	"ru":    {Tag: "ru", DecimalSep: ",", GroupSep: " ", Grouping: []int{3}, SymbolSpace: true},
+	"sv":    {Tag: "sv", DecimalSep: ",", GroupSep: " ", Grouping: []int{3}, SymbolSpace: true},
+	"ja":    {Tag: "ja", DecimalSep: ".", GroupSep: ",", Grouping: []int{3}, SymbolFirst: true, Negative: NegativeLeadingMinus},
+	"zh":    {Tag: "zh", DecimalSep: ".", GroupSep: ",", Grouping: []int{3}, SymbolFirst: true, Negative: NegativeLeadingMinus},
+}
+
+// locales holds all recognised locales and is safe for concurrent use.
+var locales = struct {
+	sync.RWMutex
+	locales map[string]Locale
+}{
+	locales: make(map[string]Locale, len(builtinLocales)),
+}
+
+func init() {
+	for _, l := range builtinLocales {
+		locales.locales[canonicalTag(l.Tag)] = l
+	}
+}
+
+// RegisterLocale adds a locale to the registry so it can be used to format
+// money. If a locale with the same tag is already registered it's replaced.
+func RegisterLocale(l Locale) error {
+	if err := l.validate(); err != nil {
+		return err
+	}
+	l.Grouping = append([]int{}, l.Grouping...)
+
+	locales.Lock()
+	defer locales.Unlock()
+	locales.locales[canonicalTag(l.Tag)] = l
+	return nil
+}
+
+// UnregisterLocale removes a locale from the registry, returning false if it
+// wasn't registered.
+func UnregisterLocale(tag string) bool {
+	locales.Lock()
+	defer locales.Unlock()
+	_, ok := locales.locales[canonicalTag(tag)]
+	delete(locales.locales, canonicalTag(tag))
+	return ok
+}
+
+// LookupLocale returns the registered locale for the passed BCP 47 language
+// tag. If the tag isn't registered, subtags are removed from the end until a
+// match is found, so "de-LU" will fall back to "de".
+func LookupLocale(tag string) (Locale, bool) {
+	locales.RLock()
+	defer locales.RUnlock()
+
+	tag = canonicalTag(tag)
+	for tag != "" {
+		if l, ok := locales.locales[tag]; ok {
+			l.Grouping = append([]int{}, l.Grouping...)
+			return l, true
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return Locale{}, false
+}
+
+// FormatLocale returns the monetary value formatted for the passed BCP 47
+// language tag, e.g. "de-DE".
+func (m Money) FormatLocale(tag string) (string, error) {
+	l, ok := LookupLocale(tag)
+	if !ok {
+		return "", fmt.Errorf("the locale '%s' is not recognised", tag)
+	}
+	return l.FormatMoney(m), nil
+}
+
+// FormatLocale returns the monetary value formatted for the passed BCP 47
+// language tag, e.g. "de-DE".
+func (b BigMoney) FormatLocale(tag string) (string, error) {
+	l, ok := LookupLocale(tag)
+	if !ok {
+		return "", fmt.Errorf("the locale '%s' is not recognised", tag)
+	}
+	return l.FormatBigMoney(b), nil
+}
+
+// FormatMoney returns the monetary value formatted for the locale.
+func (l Locale) FormatMoney(m Money) string {
+	return l.format(m.currency, fmt.Sprint(m.value))
+}
+
+// FormatBigMoney returns the monetary value formatted for the locale.
+func (l Locale) FormatBigMoney(b BigMoney) string {
+	return l.format(b.currency, b.int().String())
+}
+
+// format formats a string of subunits, as returned by strconv.FormatInt, using
+// the passed currency and the locale's conventions.
+func (l Locale) format(c Currency, str string) string {
+	sign := 1
+	if strings.HasPrefix(str, "-") {
+		sign, str = -1, str[1:]
+	} else if strings.Trim(str, "0") == "" {
+		sign = 0
+	}
+
+	sym := c.Symbol()
+	if sym == "" {
+		sym = c.Code
+	}
+	space := ""
+	if l.SymbolSpace {
+		space = " "
+	}
+
+	return l.Negative.apply(l.number(c, str), sign, func(num string) string {
+		if l.SymbolFirst {
+			return sym + space + num
+		}
+		return num + space + sym
+	})
+}
+
+// number formats an unsigned string of subunits using the locale's digit
+// grouping and decimal mark.
+func (l Locale) number(c Currency, str string) string {
+	if len(str) <= c.Subunits {
+		str = strings.Repeat("0", c.Subunits-len(str)+1) + str
+	}
+
+	units, subunits := str[:len(str)-c.Subunits], str[len(str)-c.Subunits:]
+
+	if l.GroupSep != "" && len(l.Grouping) > 0 {
+		groups := make([]string, 0)
+		for i := 0; len(units) > 0; i++ {
+			size := l.Grouping[len(l.Grouping)-1]
+			if i < len(l.Grouping) {
+				size = l.Grouping[i]
+			}
+			if size <= 0 || size >= len(units) {
+				groups = append(groups, units)
+				break
+			}
+			groups = append(groups, units[len(units)-size:])
+			units = units[:len(units)-size]
+		}
+		for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+			groups[i], groups[j] = groups[j], groups[i]
+		}
+		units = strings.Join(groups, l.GroupSep)
+	}
+
+	if c.Subunits > 0 {
+		return units + l.DecimalSep + subunits
+	}
+	return units
+}
+
+// Validate checks the locale definition can be used to format money.
+func (l Locale) validate() error {
+	if canonicalTag(l.Tag) == "" {
+		return fmt.Errorf("the locale tag '%s' is not valid", l.Tag)
+	}
+	if l.DecimalSep == "" {
+		return fmt.Errorf("the locale '%s' has no decimal mark", l.Tag)
+	}
+	if l.DecimalSep == l.GroupSep {
+		return fmt.Errorf("the locale '%s' uses the same decimal mark and grouping separator", l.Tag)
+	}
+	if !l.Negative.valid() {
+		return fmt.Errorf("the locale '%s' has an invalid negative style %d", l.Tag, l.Negative)
+	}
+	for _, size := range l.Grouping {
+		if size <= 0 {
+			return fmt.Errorf("the locale '%s' has an invalid group size of %d", l.Tag, size)
+		}
+	}
+	return nil
+}
+
+// Symbol returns the currency symbol taken from the currency's template.
+func (c Currency) Symbol() string {
+	return strings.TrimSpace(strings.Replace(c.Template, "0", "", 1))
+}
+
+// canonicalTag returns a language tag in a form suitable for comparison, with
+// underscores replaced by hyphens and the language in lower case, e.g. "en_gb"
+// becomes "en-GB".
+func canonicalTag(tag string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	for i, p := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(p)
+		case len(p) == 2:
+			parts[i] = strings.ToUpper(p)
+		case len(p) == 4:
+			parts[i] = strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+		default:
+			parts[i] = strings.ToLower(p)
+		}
+	}
+	return strings.Trim(strings.Join(parts, "-"), "-")
+}
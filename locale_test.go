@@ -0,0 +1,149 @@
+package mongo
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBuiltinLocalesAreValid(t *testing.T) {
+	for tag, l := range builtinLocales {
+		if tag != l.Tag {
+			t.Errorf("Locale %s is registered under the tag %s", l.Tag, tag)
+		}
+		if err := l.validate(); err != nil {
+			t.Errorf("Locale %s is invalid: %s", tag, err)
+		}
+	}
+}
+
+func TestFormatLocale(t *testing.T) {
+	tests := []struct {
+		code     string
+		value    int64
+		tag      string
+		expected string
+	}{
+		{"EUR", 123456, "en", "€1,234.56"},
+		{"EUR", 123456, "de-DE", "1.234,56 €"},
+		{"EUR", 123456, "fr-FR", "1 234,56 €"},
+		{"EUR", 123456, "nl", "€ 1.234,56"},
+		{"EUR", -123456, "en", "-€1,234.56"},
+		{"EUR", -123456, "de-DE", "-1.234,56 €"},
+		{"EUR", -123456, "nl", "€ -1.234,56"},
+		{"CHF", 123456789, "de-CH", "CHF 1'234'567.89"},
+		{"INR", 1234567890, "en-IN", "₹1,23,45,678.90"},
+		{"INR", 99999, "en-IN", "₹999.99"},
+		{"INR", 100000, "en-IN", "₹1,000.00"},
+		{"JPY", 1234567, "ja", "¥1,234,567"},
+		{"GBP", 5, "en-GB", "£0.05"},
+		{"GBP", -5, "en-GB", "-£0.05"},
+		{"GBP", 0, "de", "0,00 £"},
+	}
+	for _, test := range tests {
+		m, _ := MoneyFromSubunits(test.code, test.value, nil)
+		str, err := m.FormatLocale(test.tag)
+		if err != nil {
+			t.Errorf("FormatLocale(%s) failed: %s", test.tag, err)
+		}
+		if str != test.expected {
+			t.Errorf("FormatLocale(%s) of %s returned '%s', expected '%s'", test.tag, m, str, test.expected)
+		}
+	}
+
+	m, _ := MoneyFromSubunits("EUR", 123456, nil)
+	assert(t, m.String() == "€1,234.56")
+
+	if _, err := m.FormatLocale("xx"); err == nil {
+		t.Errorf("FormatLocale failed to error on unknown locale 'xx'")
+	}
+}
+
+func TestBigMoneyFormatLocale(t *testing.T) {
+	n, _ := new(big.Int).SetString("123456789012345678901234", 10)
+	b, _ := BigMoneyFromSubunits("EUR", n, nil)
+	str, err := b.FormatLocale("de")
+	if err != nil {
+		t.Errorf("FormatLocale failed: %s", err)
+	}
+	assert(t, str == "1.234.567.890.123.456.789.012,34 €")
+}
+
+func TestLookupLocale(t *testing.T) {
+	l, ok := LookupLocale("de-DE")
+	assert(t, ok && l.Tag == "de-DE")
+
+	l, ok = LookupLocale("de_de")
+	assert(t, ok && l.Tag == "de-DE")
+
+	l, ok = LookupLocale("de-LU")
+	assert(t, ok && l.Tag == "de")
+
+	l, ok = LookupLocale("fr-Latn-BE")
+	assert(t, ok && l.Tag == "fr")
+
+	_, ok = LookupLocale("xx-XX")
+	assert(t, !ok)
+
+	_, ok = LookupLocale("")
+	assert(t, !ok)
+
+	// The grouping of a registered locale can't be modified by the caller.
+	l, _ = LookupLocale("en-IN")
+	l.Grouping[0] = 1
+	l, _ = LookupLocale("en-IN")
+	assert(t, l.Grouping[0] == 3)
+}
+
+func TestRegisterLocale(t *testing.T) {
+	defer UnregisterLocale("en-ZZ")
+
+	l := Locale{Tag: "en-ZZ", DecimalSep: ",", GroupSep: "_", Grouping: []int{4}, SymbolSpace: true}
+	if err := RegisterLocale(l); err != nil {
+		t.Errorf("RegisterLocale failed: %s", err)
+	}
+
+	m, _ := MoneyFromSubunits("GBP", 123456789, nil)
+	str, _ := m.FormatLocale("en-ZZ")
+	assert(t, str == "123_4567,89 £")
+	str, _ = m.FormatLocale("en-GB")
+	assert(t, str == "£1,234,567.89")
+
+	assert(t, UnregisterLocale("en-ZZ"))
+	assert(t, !UnregisterLocale("en-ZZ"))
+	str, _ = m.FormatLocale("en-ZZ")
+	assert(t, str == "£1,234,567.89")
+
+	invalid := []Locale{
+		{Tag: "", DecimalSep: "."},
+		{Tag: "en-ZZ"},
+		{Tag: "en-ZZ", DecimalSep: ".", GroupSep: "."},
+		{Tag: "en-ZZ", DecimalSep: ".", GroupSep: ",", Grouping: []int{3, 0}},
+	}
+	for _, l := range invalid {
+		if err := RegisterLocale(l); err == nil {
+			t.Errorf("RegisterLocale failed to error on invalid locale %+v", l)
+		}
+	}
+}
+
+func TestLocaleFormatWithoutGrouping(t *testing.T) {
+	l := Locale{Tag: "en-ZZ", DecimalSep: ".", SymbolFirst: true}
+	m, _ := MoneyFromSubunits("USD", -123456789, nil)
+	assert(t, l.FormatMoney(m) == "$-1234567.89")
+}
+
+func TestCurrencySymbol(t *testing.T) {
+	tests := map[string]string{
+		"GBP": "£",
+		"EUR": "€",
+		"CHF": "CHF",
+		"DKK": "kr",
+		"BRL": "R$",
+	}
+	for code, expected := range tests {
+		c, _ := LookupCurrency(code)
+		if c.Symbol() != expected {
+			t.Errorf("Currency %s symbol is '%s', expected '%s'", code, c.Symbol(), expected)
+		}
+	}
+}
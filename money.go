@@ -1,6 +1,9 @@
 package mongo
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
@@ -13,28 +16,28 @@ import (
 // Money is the main structure that holds a monetary value and how to format it
 // as a string.
 type Money struct {
-	format currencyFormat // The currency format object.
-	value  int64          // The monetary value as a integer.
-	round  roundFunc      // The rounding function to use for division and multiplication.
+	currency Currency     // The currency definition.
+	value    int64        // The monetary value as a integer.
+	round    RoundingMode // The rounding mode to use for division and multiplication.
 }
 
 // MoneyFromSubunits constructs a new money object from an integer. The integer
 // used should represent the subunits of the currency.
 // currIsoCode is an ISO 4217 currency code.
 // value is monetary value in subunits.
-// roundFunc is a function to be used for division operations.
-func MoneyFromSubunits[T constraints.Integer](currIsoCode string, value T, f roundFunc) (Money, error) {
-	curr, ok := currencyFormats[currIsoCode]
-	if !ok {
-		return Money{}, fmt.Errorf("the currency code '%s' is not recognised", currIsoCode)
+// f is the rounding mode to be used for division operations.
+func MoneyFromSubunits[T constraints.Integer](currIsoCode string, value T, f RoundingMode) (Money, error) {
+	curr, err := lookupCurrency(currIsoCode)
+	if err != nil {
+		return Money{}, err
 	}
 	if f == nil {
 		f = RoundHalfUp
 	}
 	m := Money{
-		format: curr,
-		value:  int64(value),
-		round:  f,
+		currency: curr,
+		value:    int64(value),
+		round:    f,
 	}
 	return m, nil
 }
@@ -42,66 +45,104 @@ func MoneyFromSubunits[T constraints.Integer](currIsoCode string, value T, f rou
 // MoneyFromFloat constructs a new money object from a floating point number.
 // currIsoCode is an ISO 4217 currency code.
 // value is monetary value expressed as a float.
-// roundFunc is a function to be used for division operations.
-func MoneyFromFloat[T constraints.Float](currIsoCode string, value T, f roundFunc) (Money, error) {
-	curr, ok := currencyFormats[currIsoCode]
-	if !ok {
-		return Money{}, fmt.Errorf("the currency code '%s' is not recognised", currIsoCode)
+// f is the rounding mode to be used for division operations.
+func MoneyFromFloat[T constraints.Float](currIsoCode string, value T, f RoundingMode) (Money, error) {
+	curr, err := lookupCurrency(currIsoCode)
+	if err != nil {
+		return Money{}, err
 	}
 
-	subunits := int64(math.Round(float64(value) * (math.Pow(10, float64(curr.subunits)))))
+	subunits := int64(math.Round(float64(value) * (math.Pow(10, float64(curr.Subunits)))))
 
 	return MoneyFromSubunits(currIsoCode, subunits, f)
 }
 
 // MoneyFromString constructs a new money object from a string. Everything not
-// contained within a number is stripped out before parsing.
+// contained within a number is stripped out before parsing. Use a Parser to
+// validate the string more strictly.
 // currIsoCode is an ISO 4217 currency code.
 // str is monetary value expressed as a string.
-// roundFunc is a function to be used for division operations.
-func MoneyFromString(currIsoCode string, str string, f roundFunc) (Money, error) {
-	curr, ok := currencyFormats[currIsoCode]
-	if !ok {
-		return Money{}, fmt.Errorf("the currency code '%s' is not recognised", currIsoCode)
+// f is the rounding mode to be used for division operations.
+func MoneyFromString(currIsoCode string, str string, f RoundingMode) (Money, error) {
+	curr, err := lookupCurrency(currIsoCode)
+	if err != nil {
+		return Money{}, err
 	}
 	if f == nil {
 		f = RoundHalfUp
 	}
 
-	isNegative := strings.Contains(str, "-")
+	str, err = parseSubunits(curr, str)
+	if err != nil {
+		return Money{}, err
+	}
+
+	value, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return Money{}, err
+	}
+
+	m := Money{
+		currency: curr,
+		value:    value,
+		round:    f,
+	}
+
+	return m, nil
+}
+
+// numberRegex matches everything between the first and last number.
+var numberRegex = regexp.MustCompile("^.*?([0-9].*[0-9]).*$")
+
+// parseSubunits strips a formatted monetary value down to a signed string of
+// subunits suitable for passing to strconv.ParseInt. Negative values can be
+// presented using any of the negative styles.
+func parseSubunits(curr Currency, str string) (string, error) {
+	isNegative := isNegativeString(str)
 
 	// Remove everything before the first number and after the last number.
-	re := regexp.MustCompile("^.*?([0-9].*[0-9]).*$")
-	str = re.ReplaceAllString(str, "$1")
+	str = numberRegex.ReplaceAllString(str, "$1")
 
-	if curr.subunits > 0 {
+	if curr.Subunits > 0 {
 		// If the string is longer than the amount of subunits in this
 		// currency, we expect to see a subunit separator.
-		if len(str) > curr.subunits {
-			if string(str[len(str)-(curr.subunits+1)]) != curr.subSep {
-				return Money{}, fmt.Errorf("failed to parse string to money, no subunits defined")
+		if len(str) > curr.Subunits {
+			if string(str[len(str)-(curr.Subunits+1)]) != curr.SubSep {
+				return "", fmt.Errorf("failed to parse string to money, no subunits defined")
 			}
-			str = strings.ReplaceAll(str, curr.subSep, "")
+			str = strings.ReplaceAll(str, curr.SubSep, "")
 		}
-		str = strings.ReplaceAll(str, curr.thouSep, "")
+		str = strings.ReplaceAll(str, curr.ThouSep, "")
 	}
 
 	if isNegative {
 		str = "-" + str
 	}
 
-	value, err := strconv.ParseInt(str, 10, 64)
-	if err != nil {
-		return Money{}, err
-	}
+	return str, nil
+}
 
-	m := Money{
-		format: curr,
-		value:  value,
-		round:  f,
+// isNegativeString returns true if a formatted monetary value contains a minus
+// sign, is enclosed in parentheses or is followed by a "CR" suffix.
+func isNegativeString(str string) bool {
+	if strings.Contains(str, "-") {
+		return true
 	}
 
-	return m, nil
+	first := strings.IndexAny(str, "0123456789")
+	if first < 0 {
+		return false
+	}
+	last := strings.LastIndexAny(str, "0123456789")
+	if strings.Contains(str[:first], "(") && strings.Contains(str[last:], ")") {
+		return true
+	}
+	for _, field := range strings.Fields(str[last+1:]) {
+		if strings.EqualFold(field, "CR") {
+			return true
+		}
+	}
+	return false
 }
 
 // MoneyGBP is a helper function.
@@ -126,9 +167,22 @@ func (m Money) Clone(value int64) Money {
 	return clone
 }
 
+// Currency returns the definition of the currency the money was created with.
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// Rounding returns the rounding mode used for division and multiplication.
+func (m Money) Rounding() RoundingMode {
+	if m.round == nil {
+		return RoundHalfUp
+	}
+	return m.round
+}
+
 // IsoCode returns the ISO 4217 currency code.
 func (m Money) IsoCode() string {
-	return m.format.code
+	return m.currency.Code
 }
 
 // Value returns the entire monetary value expressed in subunits.
@@ -139,20 +193,18 @@ func (m Money) Value() int64 {
 
 // Units returns only the monetary units.
 func (m Money) Units() int64 {
-	if m.format.subunits == 0 {
+	if m.currency.Subunits == 0 {
 		return m.value
 	}
-	units := [5]int64{0, 10, 100, 1000, 10000}
-	return (m.value - m.Subunits()) / units[m.format.subunits]
+	return (m.value - m.Subunits()) / pow10(m.currency.Subunits)
 }
 
 // Subunits returns only the monetary subunits.
 func (m Money) Subunits() int64 {
-	if m.format.subunits == 0 {
+	if m.currency.Subunits == 0 {
 		return 0
 	}
-	units := [5]int64{0, 10, 100, 1000, 10000}
-	return m.value % units[m.format.subunits]
+	return m.value % pow10(m.currency.Subunits)
 }
 
 // Add is an arithmetic operator.
@@ -178,8 +230,14 @@ func (m Money) Mul(n int64) Money {
 // Div is an arithmetic operator. This operation will perform rounding of the
 // resulting value using the assigned rounding function. If you need to
 // accurately divide a money object with lossless precision, use the Split or
-// Allocate functions instead.
+// Allocate functions instead. The divisor is treated as the exact decimal it
+// represents, e.g. 1.2, so the result is exact before it's rounded.
 func (m Money) Div(f float64) Money {
+	if r, err := floatToRat(f); err == nil && r.Sign() != 0 {
+		if result, err := m.mulRat(r.Inv(r)); err == nil {
+			return result
+		}
+	}
 	m.value = m.round(float64(m.value) / f)
 	return m
 }
@@ -253,23 +311,10 @@ func (m Money) IsNeg() bool {
 // Split returns a slice containing money objects split as evenly as possible by
 // 'n' times. This operation is lossless and will account for all remainders.
 func (m Money) Split(n int64) []Money {
-	if n <= 0 {
+	s, err := m.split(n)
+	if err != nil {
 		panic("Failed to split money by zero")
 	}
-	s := make([]Money, 0, n)
-	rem := int64(math.Mod(float64(m.value), float64(n)))
-	value := int64(m.value / n)
-	var i int64
-	for i = 0; i < n; i++ {
-		if rem > 0 {
-			piece, _ := MoneyFromSubunits(m.format.code, value+1, m.round)
-			rem--
-			s = append(s, piece)
-		} else {
-			piece, _ := MoneyFromSubunits(m.format.code, value, m.round)
-			s = append(s, piece)
-		}
-	}
 	return s
 }
 
@@ -278,59 +323,208 @@ func (m Money) Split(n int64) []Money {
 // percentages of the overall sum. This operation is lossless and will account
 // for all remainders.
 func (m Money) Allocate(ratios ...int64) []Money {
+	s, err := m.allocate(ratios...)
+	if errors.Is(err, ErrOverflow) {
+		panic("Failed to allocate money, integer overflow")
+	} else if err != nil {
+		panic("Failed to allocate money, no ratios passed")
+	}
+	return s
+}
+
+// Split is the implementation of Split and SplitE.
+func (m Money) split(n int64) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("failed to split money by %d, %w", n, ErrDivideByZero)
+	}
+	s := make([]Money, n)
+	value := m.value / n
+	rem := m.value % n
+	for i := range s {
+		s[i] = m.Clone(value)
+	}
+	distributeRemainder(s, rem)
+	return s, nil
+}
+
+// Allocate is the implementation of Allocate and AllocateE. Each allocation is
+// calculated using a 128 bit intermediate value so large amounts or ratios
+// can't overflow.
+func (m Money) allocate(ratios ...int64) ([]Money, error) {
 	var sum int64 = 0
+	var overflow bool
 	for _, n := range ratios {
-		sum += n
+		if sum, overflow = addInt64(sum, n); overflow {
+			return nil, fmt.Errorf("failed to allocate money, ratios sum %w", ErrOverflow)
+		}
 	}
 	if sum <= 0 {
-		panic("Failed to allocate money, no ratios passed")
+		return nil, fmt.Errorf("failed to allocate money, ratios sum to %d, %w", sum, ErrDivideByZero)
 	}
 	s := make([]Money, 0, len(ratios))
 	var allocated int64 = 0
 	for _, n := range ratios {
-		value := m.value * n / sum
-		piece, _ := MoneyFromSubunits(m.format.code, value, m.round)
-		s = append(s, piece)
-		allocated += value
+		value, overflow := mulDivInt64(m.value, n, sum)
+		if overflow {
+			return nil, fmt.Errorf("failed to allocate %s by ratio %d/%d, %w", m, n, sum, ErrOverflow)
+		}
+		if allocated, overflow = addInt64(allocated, value); overflow {
+			return nil, fmt.Errorf("failed to allocate %s, %w", m, ErrOverflow)
+		}
+		s = append(s, m.Clone(value))
 	}
-	rem := m.value - allocated
-	for i := 0; i < len(ratios) && rem > 0; i++ {
+	rem, overflow := subInt64(m.value, allocated)
+	if overflow {
+		return nil, fmt.Errorf("failed to allocate %s, %w", m, ErrOverflow)
+	}
+	distributeRemainder(s, rem)
+	return s, nil
+}
+
+// distributeRemainder spreads a remainder of subunits one at a time across the
+// passed money objects, starting with the first.
+func distributeRemainder(s []Money, rem int64) {
+	for i := 0; i < len(s) && rem > 0; i++ {
 		s[i].value++
 		rem--
 	}
-	return s
+	for i := 0; i < len(s) && rem < 0; i++ {
+		s[i].value--
+		rem++
+	}
 }
 
-// MarshalJSON is an implementation of json.Marshaller.
+// MarshalJSON is an implementation of json.Marshaller. The rounding mode is
+// included unless it's RoundHalfUp, e.g. {"currency":"GBP","amount":"£10.55",
+// "rounding":"half-even"}.
 func (m Money) MarshalJSON() ([]byte, error) {
-	json := fmt.Sprintf(`{"currency": "%s", "amount":"%s"}`, m.IsoCode(), m.String())
+	json := fmt.Sprintf(`{"currency": "%s", "amount":"%s"%s}`, m.IsoCode(), m.String(), m.round.jsonField())
 	return []byte(json), nil
 }
 
+// UnmarshalJSON is an implementation of json.Unmarshaler. It accepts the output
+// of MarshalJSON, e.g. {"currency":"GBP","amount":"£10.55"}, or the amount
+// expressed as an integer of subunits, e.g. {"currency":"GBP","amount":1055}.
+// A bare amount string, e.g. "£10.55", is also accepted if the money object
+// being unmarshalled into already has a currency, in which case the rounding
+// mode of the receiver is kept. Otherwise RoundHalfUp is used if no rounding
+// mode is present, because MarshalJSON omits it.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	var v struct {
+		Currency string          `json:"currency"`
+		Amount   json.RawMessage `json:"amount"`
+		Rounding *RoundingMode   `json:"rounding"`
+	}
+
+	if len(b) > 0 && b[0] == '"' {
+		if m.currency.Code == "" {
+			return fmt.Errorf("failed to unmarshal money, no currency specified for amount %s", b)
+		}
+		v.Currency = m.currency.Code
+		v.Amount = b
+		v.Rounding = &m.round
+	} else if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal money, %w", err)
+	}
+
+	if v.Currency == "" {
+		return fmt.Errorf("failed to unmarshal money, no currency specified")
+	}
+	if _, err := lookupCurrency(v.Currency); err != nil {
+		return fmt.Errorf("failed to unmarshal money, %w", err)
+	}
+	if len(v.Amount) == 0 {
+		return fmt.Errorf("failed to unmarshal money, no amount specified")
+	}
+
+	round := RoundingMode(RoundHalfUp)
+	if v.Rounding != nil {
+		round = *v.Rounding
+	}
+
+	var parsed Money
+	var err error
+
+	switch v.Amount[0] {
+	case '"':
+		var str string
+		if err = json.Unmarshal(v.Amount, &str); err != nil {
+			return fmt.Errorf("failed to unmarshal money, %w", err)
+		}
+		parsed, err = MoneyFromString(v.Currency, str, round)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal money, invalid amount '%s': %w", str, err)
+		}
+	default:
+		value, err := strconv.ParseInt(string(v.Amount), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal money, amount %s is not a string or an integer of subunits", v.Amount)
+		}
+		parsed, _ = MoneyFromSubunits(v.Currency, value, round)
+	}
+
+	*m = parsed
+	return nil
+}
+
 // String is an implementation of fmt.Stringer and returns the string
 // formatted representation of the monetary value.
 func (m Money) String() string {
-	return strings.Replace(m.format.template, "0", m.StringNoSymbol(), 1)
+	return m.StringStyle(m.currency.Negative)
+}
+
+// StringStyle returns the string formatted representation of the monetary
+// value, presenting negative values using the passed style.
+func (m Money) StringStyle(s NegativeStyle) string {
+	return formatTemplate(m.currency, strconv.FormatInt(m.value, 10), s)
 }
 
 // StringNoSymbol returns the string formatted representation of the monetary
 // value without a currency symbol.
 func (m Money) StringNoSymbol() string {
-	str := strconv.FormatInt(m.value, 10)
+	return formatSubunits(m.currency, strconv.FormatInt(m.value, 10))
+}
+
+// formatTemplate formats a string of subunits, as returned by
+// strconv.FormatInt, using the passed currency's template and negative style.
+func formatTemplate(c Currency, str string, s NegativeStyle) string {
+	sign := 1
+	if strings.HasPrefix(str, "-") {
+		sign, str = -1, str[1:]
+	} else if strings.Trim(str, "0") == "" {
+		sign = 0
+	}
+	return s.apply(formatSubunits(c, str), sign, func(num string) string {
+		return strings.Replace(c.Template, "0", num, 1)
+	})
+}
+
+// formatSubunits formats a string of subunits, as returned by
+// strconv.FormatInt, using the passed currency format.
+func formatSubunits(c Currency, str string) string {
+	sign := ""
+	if strings.HasPrefix(str, "-") {
+		sign, str = "-", str[1:]
+	}
 
-	if len(str) <= m.format.subunits {
-		str = strings.Repeat("0", m.format.subunits-len(str)+1) + str
+	if len(str) <= c.Subunits {
+		str = strings.Repeat("0", c.Subunits-len(str)+1) + str
 	}
 
-	if m.format.thouSep != "" {
-		for i := len(str) - m.format.subunits - 3; i > 0; i -= 3 {
-			str = str[:i] + m.format.thouSep + str[i:]
+	if c.ThouSep != "" {
+		for i := len(str) - c.Subunits - 3; i > 0; i -= 3 {
+			str = str[:i] + c.ThouSep + str[i:]
 		}
 	}
 
-	if m.format.subunits > 0 {
-		str = str[:len(str)-m.format.subunits] + m.format.subSep + str[len(str)-m.format.subunits:]
+	if c.Subunits > 0 {
+		str = str[:len(str)-c.Subunits] + c.SubSep + str[len(str)-c.Subunits:]
 	}
 
-	return str
+	return sign + str
 }
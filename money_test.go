@@ -574,3 +574,120 @@ func TestMoneyJsonMarshalling(t *testing.T) {
 	bytes, _ = json.Marshal(resp)
 	assertJSON(t, bytes, `{"name":"Widget","cost":{"currency":"GBP","amount":"£10.99"}}`)
 }
+
+func TestMoneyJsonUnmarshalling(t *testing.T) {
+	type Response struct {
+		Name string `json:"name"`
+		Cost Money  `json:"cost"`
+	}
+
+	var m Money
+	err := json.Unmarshal([]byte(`{"currency":"GBP","amount":"£10.99"}`), &m)
+	if err != nil {
+		t.Errorf("Money failed to unmarshal: %s", err)
+	}
+	assertMoneyString(t, m, "GBP", "£10.99")
+
+	err = json.Unmarshal([]byte(`{"currency":"JOD","amount":2462486}`), &m)
+	if err != nil {
+		t.Errorf("Money failed to unmarshal subunits: %s", err)
+	}
+	assertMoneyString(t, m, "JOD", "2,462.486 د.أ")
+
+	var resp Response
+	err = json.Unmarshal([]byte(`{"name":"Widget","cost":{"currency":"EUR","amount":"-€1,451.39"}}`), &resp)
+	if err != nil {
+		t.Errorf("Response failed to unmarshal: %s", err)
+	}
+	assertMoneyValue(t, resp.Cost, -145139)
+	assertMoneyString(t, resp.Cost, "EUR", "€-1,451.39")
+}
+
+func TestMoneyJsonRoundTrip(t *testing.T) {
+	x, _ := MoneyFromSubunits("CLF", 1578964418, RoundDown)
+	bytes, _ := json.Marshal(x)
+
+	y, _ := MoneyFromSubunits("GBP", 0, RoundUp)
+	err := json.Unmarshal(bytes, &y)
+	if err != nil {
+		t.Errorf("Money failed to unmarshal: %s", err)
+	}
+	assert(t, x.Eq(y))
+	assertValue(t, y.Div(3).Value(), 526321472)
+
+	// RoundHalfUp isn't encoded, so it's used if no rounding mode is present.
+	x, _ = MoneyFromSubunits("GBP", 1000, RoundHalfUp)
+	bytes, _ = json.Marshal(x)
+	y, _ = MoneyFromSubunits("GBP", 0, RoundDown)
+	err = json.Unmarshal(bytes, &y)
+	if err != nil {
+		t.Errorf("Money failed to unmarshal: %s", err)
+	}
+	assert(t, y.Rounding().String() == "HalfUp")
+	assertValue(t, y.Div(6).Value(), 167)
+
+	// The receiver's rounding mode is kept for a bare amount.
+	y, _ = MoneyFromSubunits("CLF", 0, RoundDown)
+	err = json.Unmarshal([]byte(`"UF1,0000"`), &y)
+	if err != nil {
+		t.Errorf("Money failed to unmarshal bare amount: %s", err)
+	}
+	assertMoneyValue(t, y, 10000)
+	assert(t, y.Rounding().String() == "Floor")
+}
+
+func TestMoneyJsonUnmarshallingErrors(t *testing.T) {
+	var m Money
+	for _, str := range []string{
+		`{"currency":"XXX","amount":"£10.99"}`,
+		`{"amount":"£10.99"}`,
+		`{"currency":"GBP"}`,
+		`{"currency":"GBP","amount":10.99}`,
+		`{"currency":"GBP","amount":"£10.9"}`,
+		`{"currency":"GBP","amount":true}`,
+		`"£10.99"`,
+		`[]`,
+	} {
+		if err := json.Unmarshal([]byte(str), &m); err == nil {
+			t.Errorf("Money failed to error when unmarshalling %s", str)
+		}
+	}
+}
+
+func TestMoneySplitNegative(t *testing.T) {
+	x, _ := MoneyGBP(-100)
+	s := x.Split(3)
+	assertMoneyValue(t, s[0], -34)
+	assertMoneyValue(t, s[1], -33)
+	assertMoneyValue(t, s[2], -33)
+	assertMoneyValue(t, s[0].Add(s[1]).Add(s[2]), -100)
+
+	x, _ = MoneyGBP(math.MaxInt64)
+	s = x.Split(2)
+	assertMoneyValue(t, s[0], math.MaxInt64/2+1)
+	assertMoneyValue(t, s[1], math.MaxInt64/2)
+}
+
+func TestMoneyAllocateNegative(t *testing.T) {
+	x, _ := MoneyGBP(-1099)
+	s := x.Allocate(30, 70)
+	assertMoneyValue(t, s[0], -330)
+	assertMoneyValue(t, s[1], -769)
+	assertMoneyValue(t, s[0].Add(s[1]), -1099)
+}
+
+func TestMoneyAllocateLarge(t *testing.T) {
+	x, _ := MoneyGBP(math.MaxInt64 - 1)
+	s := x.Allocate(654, 465, 45565, 65, 4, 6542, 54, 574, 564, 6544, 9, 2342342, 237, 45, 34325, 2221, 111, 577, 7)
+	total := s[0]
+	for i := 1; i < len(s); i++ {
+		total = total.Add(s[i])
+	}
+	assertMoneyValue(t, total, math.MaxInt64-1)
+}
+
+func TestMoneyAllocateOverflow(t *testing.T) {
+	x, _ := MoneyGBP(math.MaxInt64)
+	defer assertPanic(t)
+	x.Allocate(3, -1)
+}
@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"math"
+	"math/bits"
+)
+
+// addInt64 returns a + b and whether the operation overflowed.
+func addInt64(a, b int64) (int64, bool) {
+	c := a + b
+	return c, (b > 0 && c < a) || (b < 0 && c > a)
+}
+
+// subInt64 returns a - b and whether the operation overflowed.
+func subInt64(a, b int64) (int64, bool) {
+	c := a - b
+	return c, (b > 0 && c > a) || (b < 0 && c < a)
+}
+
+// mulInt64 returns a * b and whether the operation overflowed.
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, false
+	}
+	c := a * b
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return c, true
+	}
+	return c, c/b != a
+}
+
+// mulDivInt64 returns a * b / c truncated toward zero and whether the result
+// overflowed. The intermediate product is calculated using 128 bits so it
+// can't overflow itself.
+func mulDivInt64(a, b, c int64) (int64, bool) {
+	if c == 0 {
+		return 0, true
+	}
+
+	neg := (a < 0) != (b < 0) != (c < 0)
+
+	hi, lo := bits.Mul64(absInt64(a), absInt64(b))
+	d := absInt64(c)
+	if hi >= d {
+		return 0, true
+	}
+	q, _ := bits.Div64(hi, lo, d)
+
+	if neg {
+		if q > 1<<63 {
+			return 0, true
+		}
+		return int64(-q), false
+	}
+	if q > math.MaxInt64 {
+		return 0, true
+	}
+	return int64(q), false
+}
+
+// absInt64 returns the absolute value of n as an unsigned integer, which is
+// able to represent the absolute value of math.MinInt64.
+func absInt64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAddInt64(t *testing.T) {
+	c, o := addInt64(math.MaxInt64-1, 1)
+	assert(t, !o && c == math.MaxInt64)
+
+	_, o = addInt64(math.MaxInt64, 1)
+	assert(t, o)
+
+	_, o = addInt64(math.MinInt64, -1)
+	assert(t, o)
+
+	c, o = addInt64(math.MinInt64, math.MaxInt64)
+	assert(t, !o && c == -1)
+}
+
+func TestSubInt64(t *testing.T) {
+	c, o := subInt64(math.MinInt64+1, 1)
+	assert(t, !o && c == math.MinInt64)
+
+	_, o = subInt64(math.MinInt64, 1)
+	assert(t, o)
+
+	_, o = subInt64(math.MaxInt64, -1)
+	assert(t, o)
+
+	_, o = subInt64(0, math.MinInt64)
+	assert(t, o)
+}
+
+func TestMulInt64(t *testing.T) {
+	c, o := mulInt64(math.MaxInt64, -1)
+	assert(t, !o && c == -math.MaxInt64)
+
+	_, o = mulInt64(math.MinInt64, -1)
+	assert(t, o)
+
+	_, o = mulInt64(-1, math.MinInt64)
+	assert(t, o)
+
+	_, o = mulInt64(1<<32, 1<<31)
+	assert(t, o)
+
+	c, o = mulInt64(1<<31, 1<<31)
+	assert(t, !o && c == 1<<62)
+}
+
+func TestMulDivInt64(t *testing.T) {
+	c, o := mulDivInt64(math.MaxInt64, math.MaxInt64, math.MaxInt64)
+	assert(t, !o && c == math.MaxInt64)
+
+	c, o = mulDivInt64(math.MinInt64, 3, 4)
+	assert(t, !o && c == math.MinInt64/4*3)
+
+	c, o = mulDivInt64(-7, 2, 3)
+	assert(t, !o && c == -4)
+
+	c, o = mulDivInt64(math.MinInt64, 1, 1)
+	assert(t, !o && c == math.MinInt64)
+
+	_, o = mulDivInt64(math.MinInt64, -1, 1)
+	assert(t, o)
+
+	_, o = mulDivInt64(math.MaxInt64, 2, 1)
+	assert(t, o)
+
+	_, o = mulDivInt64(1, 1, 0)
+	assert(t, o)
+}
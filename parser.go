@@ -0,0 +1,318 @@
+package mongo
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+// ParseMode specifies how strictly a parser validates its input.
+type ParseMode int
+
+const (
+	// ParseStrict only accepts correctly grouped numbers with no more fraction
+	// digits than the currency has subunits. Any text around the number must
+	// be the currency's symbol or code.
+	ParseStrict ParseMode = iota
+
+	// ParseLenient ignores digit grouping and text around the number. Fraction
+	// digits beyond the currency's subunits are rounded using the rounding
+	// function.
+	ParseLenient
+)
+
+// ParseError is returned when a string can't be parsed as money.
+type ParseError struct {
+	Input string // The string being parsed.
+	Pos   int    // The byte offset of the problem within the input.
+	Msg   string // A description of the problem.
+	Err   error  // The underlying error, if any.
+}
+
+// Error is an implementation of error.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse '%s' as money at position %d, %s", e.Input, e.Pos, e.Msg)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parser parses formatted monetary values such as "£1,234.56", "(1.234,56 €)"
+// or "10.55 CR". The zero value is a strict parser that uses the separators of
+// the currency being parsed.
+type Parser struct {
+	Mode       ParseMode // How strictly the input is validated.
+	DecimalSep string    // The decimal mark. If empty the currency's separators are used.
+	GroupSep   string    // The digit grouping separator, or empty if digits are not grouped.
+	Grouping   []int     // The digit group sizes from the right, the last size repeats. Defaults to {3}.
+}
+
+// ParserForLocale returns a parser that uses the separators and grouping of
+// the passed BCP 47 language tag.
+func ParserForLocale(tag string, mode ParseMode) (Parser, error) {
+	l, ok := LookupLocale(tag)
+	if !ok {
+		return Parser{}, fmt.Errorf("the locale '%s' is not recognised", tag)
+	}
+	p := Parser{
+		Mode:       mode,
+		DecimalSep: l.DecimalSep,
+		GroupSep:   l.GroupSep,
+		Grouping:   l.Grouping,
+	}
+	return p, nil
+}
+
+// Parse constructs a new money object from a formatted string.
+// currIsoCode is an ISO 4217 currency code.
+// str is monetary value expressed as a string.
+// f is the rounding mode to be used for division operations.
+func (p Parser) Parse(currIsoCode string, str string, f RoundingMode) (Money, error) {
+	curr, err := lookupCurrency(currIsoCode)
+	if err != nil {
+		return Money{}, err
+	}
+	if f == nil {
+		f = RoundHalfUp
+	}
+
+	value, err := p.parse(curr, str, f)
+	if err != nil {
+		return Money{}, err
+	}
+	if !value.IsInt64() {
+		return Money{}, &ParseError{Input: str, Pos: 0, Msg: "the value is too large", Err: ErrOverflow}
+	}
+
+	m := Money{
+		currency: curr,
+		value:    value.Int64(),
+		round:    f,
+	}
+	return m, nil
+}
+
+// ParseBig constructs a new big money object from a formatted string.
+// currIsoCode is an ISO 4217 currency code.
+// str is monetary value expressed as a string.
+// f is the rounding mode to be used for division operations.
+func (p Parser) ParseBig(currIsoCode string, str string, f RoundingMode) (BigMoney, error) {
+	curr, err := lookupCurrency(currIsoCode)
+	if err != nil {
+		return BigMoney{}, err
+	}
+	if f == nil {
+		f = RoundHalfUp
+	}
+
+	value, err := p.parse(curr, str, f)
+	if err != nil {
+		return BigMoney{}, err
+	}
+
+	b := BigMoney{
+		currency: curr,
+		value:    value,
+		round:    f,
+	}
+	return b, nil
+}
+
+// rules returns the decimal mark, grouping separator and group sizes to use
+// when parsing the passed currency.
+func (p Parser) rules(c Currency) (string, string, []int) {
+	dec, grp := p.DecimalSep, p.GroupSep
+	if dec == "" {
+		dec, grp = c.SubSep, c.ThouSep
+		if dec == "" {
+			dec = "."
+		}
+	}
+	grouping := p.Grouping
+	if len(grouping) == 0 {
+		grouping = []int{3}
+	}
+	return dec, grp, grouping
+}
+
+// parse returns the subunits represented by the formatted string.
+func (p Parser) parse(c Currency, str string, f RoundingMode) (*big.Int, error) {
+	dec, grp, grouping := p.rules(c)
+	strict := p.Mode == ParseStrict
+
+	fail := func(pos int, format string, a ...interface{}) error {
+		return &ParseError{Input: str, Pos: pos, Msg: fmt.Sprintf(format, a...)}
+	}
+
+	start := strings.IndexFunc(str, isASCIIDigit)
+	if start < 0 {
+		return nil, fail(len(str), "no digits found")
+	}
+
+	// Scan the number, recording the size of each group of integer digits.
+	var units, fraction strings.Builder
+	var groups, groupPos []int
+	decPos, size, end := -1, 0, start
+
+	for end < len(str) {
+		if isASCIIDigit(rune(str[end])) {
+			if decPos >= 0 {
+				fraction.WriteByte(str[end])
+			} else {
+				units.WriteByte(str[end])
+				size++
+			}
+			end++
+			continue
+		}
+		if decPos < 0 && followedByDigit(str, end, dec) {
+			decPos = end
+			end += len(dec)
+			continue
+		}
+		if decPos < 0 && grp != "" && followedByDigit(str, end, grp) {
+			groups = append(groups, size)
+			groupPos = append(groupPos, end)
+			size = 0
+			end += len(grp)
+			continue
+		}
+		break
+	}
+	groups = append(groups, size)
+
+	if strict && len(groups) > 1 {
+		for i := len(groups) - 1; i >= 0; i-- {
+			n := len(groups) - 1 - i
+			expected := grouping[len(grouping)-1]
+			if n < len(grouping) {
+				expected = grouping[n]
+			}
+			if i > 0 && groups[i] != expected {
+				return nil, fail(groupPos[i-1], "expected a group of %d digits", expected)
+			}
+			if i == 0 && groups[i] > expected {
+				return nil, fail(start, "expected a group of no more than %d digits", expected)
+			}
+		}
+	}
+
+	if strict && fraction.Len() > c.Subunits {
+		return nil, fail(decPos+len(dec)+c.Subunits, "the currency '%s' only has %d subunits", c.Code, c.Subunits)
+	}
+
+	prefix, suffix := []byte(str[:start]), []byte(str[end:])
+
+	if i := strings.IndexFunc(string(suffix), isASCIIDigit); i >= 0 {
+		return nil, fail(end+i, "unexpected digit after the number")
+	}
+	if strings.HasSuffix(string(prefix), dec) && !strings.HasSuffix(c.Symbol(), dec) {
+		return nil, fail(start-len(dec), "expected a digit before the decimal mark")
+	}
+
+	// Find and remove the sign markers.
+	negative := false
+	var signs []int
+
+	open := strings.Index(string(prefix), "(")
+	close := strings.LastIndex(string(suffix), ")")
+	if open >= 0 && close < 0 {
+		return nil, fail(open, "unbalanced parenthesis")
+	}
+	if close >= 0 && open < 0 {
+		return nil, fail(end+close, "unbalanced parenthesis")
+	}
+	if open >= 0 {
+		negative = true
+		signs = append(signs, open)
+		prefix[open], suffix[close] = ' ', ' '
+	}
+
+	for i, ch := range prefix {
+		if ch == '-' || ch == '+' {
+			negative = ch == '-'
+			signs = append(signs, i)
+			prefix[i] = ' '
+		}
+	}
+	for i, ch := range suffix {
+		if ch == '-' {
+			negative = true
+			signs = append(signs, end+i)
+			suffix[i] = ' '
+		}
+	}
+
+	// A "CR" or "DR" suffix must be the last word.
+	trimmed := strings.TrimRightFunc(string(suffix), unicode.IsSpace)
+	if n := len(trimmed); n >= 2 && (n == 2 || unicode.IsSpace(rune(trimmed[n-3]))) {
+		marker := trimmed[n-2:]
+		if strings.EqualFold(marker, "CR") || strings.EqualFold(marker, "DR") {
+			if strings.EqualFold(marker, "CR") {
+				negative = true
+			}
+			signs = append(signs, end+n-2)
+			suffix = suffix[:n-2]
+		}
+	}
+
+	if len(signs) > 1 {
+		slices.Sort(signs)
+		return nil, fail(signs[1], "more than one sign")
+	}
+
+	// Check any remaining text is the currency's symbol or code.
+	if strict {
+		before := strings.TrimSpace(string(prefix))
+		after := strings.TrimSpace(string(suffix))
+		if before != "" && after != "" {
+			return nil, fail(end+strings.IndexFunc(string(suffix), isNotSpace), "unexpected text '%s'", after)
+		}
+		if text := before + after; text != "" && text != c.Symbol() && text != c.Code {
+			pos := strings.IndexFunc(string(prefix), isNotSpace)
+			if before == "" {
+				pos = end + strings.IndexFunc(string(suffix), isNotSpace)
+			}
+			return nil, fail(pos, "unexpected text '%s'", text)
+		}
+	}
+
+	// The sign is applied before rounding so asymmetric rounding functions
+	// round negative amounts the right way.
+	value, _ := new(big.Int).SetString(units.String()+fraction.String(), 10)
+	if negative {
+		value.Neg(value)
+	}
+	if n := fraction.Len() - c.Subunits; n > 0 {
+		den := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+		value = roundRat(new(big.Rat).SetFrac(value, den), f)
+	} else if n < 0 {
+		value.Mul(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-n)), nil))
+	}
+	return value, nil
+}
+
+// followedByDigit returns true if the string contains the separator at the
+// passed position and it's immediately followed by a digit.
+func followedByDigit(str string, pos int, sep string) bool {
+	if !strings.HasPrefix(str[pos:], sep) {
+		return false
+	}
+	next := pos + len(sep)
+	return next < len(str) && isASCIIDigit(rune(str[next]))
+}
+
+// isASCIIDigit returns true if the rune is a digit between 0 and 9.
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isNotSpace returns true if the rune is not white space.
+func isNotSpace(r rune) bool {
+	return !unicode.IsSpace(r)
+}
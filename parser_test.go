@@ -0,0 +1,182 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParserStrict(t *testing.T) {
+	tests := []struct {
+		code     string
+		str      string
+		expected int64
+	}{
+		{"GBP", "£1,234.56", 123456},
+		{"GBP", "1234.56", 123456},
+		{"GBP", "1.5", 150},
+		{"GBP", "£1", 100},
+		{"GBP", "GBP 10.55", 1055},
+		{"GBP", "10.55 GBP", 1055},
+		{"GBP", "-£10.55", -1055},
+		{"GBP", "£-10.55", -1055},
+		{"GBP", "+£10.55", 1055},
+		{"GBP", "(£10.55)", -1055},
+		{"GBP", "£10.55-", -1055},
+		{"GBP", "£10.55 CR", -1055},
+		{"GBP", "£10.55 dr", 1055},
+		{"GBP", "  £1,234,567.89  ", 123456789},
+		{"CHF", "-10.55 CHF", -1055},
+		{"JPY", "¥1,234", 1234},
+		{"BHD", "1.5", 1500},
+		{"BOB", "Bs.10.55", 1055},
+		{"XDR", "10 SDR", 10},
+		{"EUR", "€0.01", 1},
+	}
+	var p Parser
+	for _, test := range tests {
+		m, err := p.Parse(test.code, test.str, nil)
+		if err != nil {
+			t.Errorf("Parse(%s) failed: %s", test.str, err)
+			continue
+		}
+		if m.Value() != test.expected {
+			t.Errorf("Parse(%s) returned %d, expected %d", test.str, m.Value(), test.expected)
+		}
+	}
+}
+
+func TestParserStrictErrors(t *testing.T) {
+	tests := []struct {
+		code string
+		str  string
+		pos  int
+	}{
+		{"GBP", "", 0},
+		{"GBP", "£", 2},
+		{"GBP", "10-11", 3},
+		{"GBP", "12,34,56", 5},
+		{"GBP", "1,2345.00", 1},
+		{"GBP", "1234,567.00", 0},
+		{"GBP", "1.555", 4},
+		{"GBP", "$10.55", 0},
+		{"GBP", "£10.55 GBP", 8},
+		{"GBP", "(£10.55", 0},
+		{"GBP", "£10.55)", 7},
+		{"GBP", "-(£10.55)", 1},
+		{"GBP", "-£10.55 CR", 9},
+		{"GBP", "£.55", 2},
+		{"GBP", "1.234,56", 4},
+		{"JPY", "¥1.5", 4},
+	}
+	var p Parser
+	for _, test := range tests {
+		_, err := p.Parse(test.code, test.str, nil)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Parse(%s) failed to return a ParseError, returned %v", test.str, err)
+			continue
+		}
+		if perr.Pos != test.pos || perr.Input != test.str {
+			t.Errorf("Parse(%s) returned an error at position %d, expected %d: %s", test.str, perr.Pos, test.pos, perr)
+		}
+	}
+}
+
+func TestParserLenient(t *testing.T) {
+	tests := []struct {
+		str      string
+		f        RoundingMode
+		expected int64
+	}{
+		{"12,34,56", nil, 12345600},
+		{"Total: £10.55 (approx", nil, 1055},
+		{"£1.555", nil, 156},
+		{"£1.555", RoundDown, 155},
+		{"-£1.555", RoundHalfToEven, -156},
+		{"£10.55 GBP", nil, 1055},
+		{"-1.005", RoundCeiling, -100},
+		{"-1.005", RoundFloor, -101},
+		{"-1.005", RoundAwayFromZero, -101},
+		{"-1.005", RoundTowardZero, -100},
+		{"(1.005)", RoundCeiling, -100},
+		{"(1.005)", RoundFloor, -101},
+		{"1.005", RoundCeiling, 101},
+		{"1.005", RoundFloor, 100},
+	}
+	p := Parser{Mode: ParseLenient}
+	for _, test := range tests {
+		m, err := p.Parse("GBP", test.str, test.f)
+		if err != nil {
+			t.Errorf("Parse(%s) failed: %s", test.str, err)
+			continue
+		}
+		if m.Value() != test.expected {
+			t.Errorf("Parse(%s) returned %d, expected %d", test.str, m.Value(), test.expected)
+		}
+	}
+
+	for _, str := range []string{"10-11", "£.55", "-(10.55)"} {
+		if _, err := p.Parse("GBP", str, nil); err == nil {
+			t.Errorf("Parse(%s) failed to error in lenient mode", str)
+		}
+	}
+}
+
+func TestParserExplicitRules(t *testing.T) {
+	p := Parser{DecimalSep: ",", GroupSep: ".", Grouping: []int{3}}
+	m, err := p.Parse("EUR", "1.234,56 €", nil)
+	assert(t, err == nil)
+	assertMoneyValue(t, m, 123456)
+
+	p = Parser{DecimalSep: "."}
+	m, err = p.Parse("GBP", "1234567.89", nil)
+	assert(t, err == nil)
+	assertMoneyValue(t, m, 123456789)
+
+	_, err = p.Parse("GBP", "1,234.56", nil)
+	assert(t, err != nil)
+}
+
+func TestParserForLocale(t *testing.T) {
+	tests := []struct {
+		tag      string
+		code     string
+		str      string
+		expected int64
+	}{
+		{"de-DE", "EUR", "1.234,56 €", 123456},
+		{"fr-FR", "EUR", "1 234,56 €", 123456},
+		{"en-IN", "INR", "₹1,23,45,678.90", 1234567890},
+		{"de-CH", "CHF", "CHF 1'234.56", 123456},
+	}
+	for _, test := range tests {
+		p, err := ParserForLocale(test.tag, ParseStrict)
+		if err != nil {
+			t.Errorf("ParserForLocale(%s) failed: %s", test.tag, err)
+			continue
+		}
+		m, err := p.Parse(test.code, test.str, nil)
+		if err != nil {
+			t.Errorf("Parse(%s) failed: %s", test.str, err)
+			continue
+		}
+		assertMoneyValue(t, m, test.expected)
+	}
+
+	p, _ := ParserForLocale("en-IN", ParseStrict)
+	_, err := p.Parse("INR", "₹1,234,567.00", nil)
+	assert(t, err != nil)
+
+	_, err = ParserForLocale("xx", ParseStrict)
+	assert(t, err != nil)
+}
+
+func TestParserOverflow(t *testing.T) {
+	var p Parser
+	_, err := p.Parse("GBP", "£100,000,000,000,000,000.00", nil)
+	assert(t, errors.Is(err, ErrOverflow))
+
+	b, err := p.ParseBig("GBP", "£100,000,000,000,000,000.00", nil)
+	assert(t, err == nil)
+	assert(t, b.Value().String() == "10000000000000000000")
+}
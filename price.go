@@ -2,23 +2,27 @@ package mongo
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 
 	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
 )
 
 // Price is a structure that holds a price and gives information about the
 // amount of tax applied to that price.
 type Price struct {
-	gross Money // The gross.
-	taxes taxes // The amount of tax subtracted from the gross to produce the net.
+	gross     Money     // The gross.
+	taxes     taxes     // The amount of tax subtracted from the gross to produce the net.
+	discounts discounts // The discounts already taken off the gross.
 }
 
 // PriceFromSubunits constructs a new price object from an integer.
 // currIsoCode is an ISO 4217 currency code.
 // gross is monetary value in subunits.
-// roundFunc is a function to be used for division operations.
-func PriceFromSubunits[T constraints.Integer](currIsoCode string, gross T, f roundFunc) (Price, error) {
+// f is the rounding mode to be used for division operations.
+func PriceFromSubunits[T constraints.Integer](currIsoCode string, gross T, f RoundingMode) (Price, error) {
 	var price Price
 	var err error
 
@@ -29,7 +33,7 @@ func PriceFromSubunits[T constraints.Integer](currIsoCode string, gross T, f rou
 
 	price.taxes = taxes{
 		total:  price.gross.Clone(0),
-		detail: make(map[string]Money, 0),
+		detail: make(detail, 0),
 	}
 
 	return price, nil
@@ -38,8 +42,8 @@ func PriceFromSubunits[T constraints.Integer](currIsoCode string, gross T, f rou
 // PriceFromFloat constructs a new price object from a floating point number.
 // currIsoCode is an ISO 4217 currency code.
 // gross is monetary value expressed as a float.
-// roundFunc is a function to be used for division operations.
-func PriceFromFloat[T constraints.Float](currIsoCode string, gross T, f roundFunc) (Price, error) {
+// f is the rounding mode to be used for division operations.
+func PriceFromFloat[T constraints.Float](currIsoCode string, gross T, f RoundingMode) (Price, error) {
 	var price Price
 	var err error
 
@@ -50,7 +54,7 @@ func PriceFromFloat[T constraints.Float](currIsoCode string, gross T, f roundFun
 
 	price.taxes = taxes{
 		total:  price.gross.Clone(0),
-		detail: make(map[string]Money, 0),
+		detail: make(detail, 0),
 	}
 
 	return price, nil
@@ -60,8 +64,8 @@ func PriceFromFloat[T constraints.Float](currIsoCode string, gross T, f roundFun
 // contained within a number is stripped out before parsing.
 // currIsoCode is an ISO 4217 currency code.
 // gross is monetary value expressed as a string.
-// roundFunc is a function to be used for division operations.
-func PriceFromString(currIsoCode string, gross string, f roundFunc) (Price, error) {
+// f is the rounding mode to be used for division operations.
+func PriceFromString(currIsoCode string, gross string, f RoundingMode) (Price, error) {
 	var price Price
 	var err error
 
@@ -72,7 +76,7 @@ func PriceFromString(currIsoCode string, gross string, f roundFunc) (Price, erro
 
 	price.taxes = taxes{
 		total:  price.gross.Clone(0),
-		detail: make(map[string]Money, 0),
+		detail: make(detail, 0),
 	}
 
 	return price, nil
@@ -96,17 +100,49 @@ func PriceGBP[T constraints.Integer](gross T, vat float64) (Price, error) {
 func (p *Price) AddTax(m Money, desc string) {
 	assertSameMoneyCurrency(p.gross, m)
 	t := p.gross.Clone(m.value)
-	p.taxes = p.taxes.add(desc, t)
+	p.taxes = p.taxes.add(UnitTax(desc, t), t)
 	p.gross = p.gross.Add(t)
 }
 
 // AddTaxPercentage adds a tax to the price using a percentage.
-// This will literally add a percentage to the gross price.
+// This will literally add a percentage to the gross price. The percentage is
+// treated as the exact decimal it represents, e.g. 17.5, and the tax is
+// rounded once using the gross price's rounding function. The tax is charged
+// on every earlier tax, use AddTaxes to choose what a tax is charged on.
+// If the tax can't be added, e.g. the percentage is NaN or the gross price
+// would overflow, the price is left unchanged. Use AddTaxDecimal or AddTaxes
+// to be told about such errors.
 func (p *Price) AddTaxPercent(percent float64, desc string) {
-	v := (float64(p.gross.value) / 100) * percent
-	t := p.gross.Clone(p.gross.round(v))
-	p.taxes = p.taxes.add(desc, t)
-	p.gross = p.gross.Add(t)
+	if r, err := floatToRat(percent); err == nil {
+		_ = p.addTaxRat(r, desc)
+	}
+}
+
+// AddTaxDecimal adds a tax to the price using a percentage expressed as a
+// decimal string, e.g. "17.5". This will literally add a percentage to the
+// gross price. The tax is calculated exactly and rounded once using the gross
+// price's rounding function.
+func (p *Price) AddTaxDecimal(percent string, desc string) error {
+	r, err := parseDecimal(percent)
+	if err != nil {
+		return fmt.Errorf("failed to add tax '%s', %w", desc, err)
+	}
+	return p.addTaxRat(r, desc)
+}
+
+// addTaxRat adds a tax to the price using an exact percentage.
+func (p *Price) addTaxRat(percent *big.Rat, desc string) error {
+	t, err := p.gross.mulRat(new(big.Rat).Quo(percent, big.NewRat(100, 1)))
+	if err != nil {
+		return fmt.Errorf("failed to add tax '%s', %w", desc, err)
+	}
+	gross, err := p.gross.AddE(t)
+	if err != nil {
+		return fmt.Errorf("failed to add tax '%s', %w", desc, err)
+	}
+	p.taxes = p.taxes.add(Tax{Description: desc, Base: TaxOnGross, Rate: percent}, t)
+	p.gross = gross
+	return nil
 }
 
 // IncludeTax adds a tax to the price using a money value.
@@ -114,19 +150,407 @@ func (p *Price) AddTaxPercent(percent float64, desc string) {
 func (p *Price) IncludeTax(m Money, desc string) {
 	assertSameMoneyCurrency(p.gross, m)
 	t := p.gross.Clone(m.value)
-	p.taxes = p.taxes.add(desc, t)
+	tax := UnitTax(desc, t)
+	tax.Mode = TaxInclusive
+	p.taxes = p.taxes.add(tax, t)
 }
 
 // IncludeTaxPercent adds a tax to the price using a percentage.
-// This implies this tax is already included in the gross price.
+// This implies this tax is already included in the gross price. The
+// percentage is treated as the exact decimal it represents, e.g. 17.5, and the
+// tax is rounded once using the gross price's rounding function. The tax is
+// taken out of the net, so taxes already included are charged on it, and it's
+// recorded in front of them. If the tax can't be included, e.g. the
+// percentage is NaN or -100, the price is left unchanged. Use
+// IncludeTaxDecimal or IncludeTaxes to be told about such errors.
 func (p *Price) IncludeTaxPercent(percent float64, desc string) {
-	t := p.Net().Sub(p.Net().Div(1 + (percent / 100)))
-	p.taxes = p.taxes.add(desc, t)
+	if r, err := floatToRat(percent); err == nil {
+		_ = p.includeTaxRat(r, desc)
+	}
+}
+
+// IncludeTaxDecimal adds a tax to the price using a percentage expressed as a
+// decimal string, e.g. "17.5". This implies this tax is already included in
+// the gross price. The tax is calculated exactly and rounded once using the
+// gross price's rounding function. Like IncludeTaxPercent it's taken out of
+// the net and recorded in front of the taxes already included, so it can't be
+// used with taxes on the net or compound taxes.
+func (p *Price) IncludeTaxDecimal(percent string, desc string) error {
+	r, err := parseDecimal(percent)
+	if err != nil {
+		return fmt.Errorf("failed to include tax '%s', %w", desc, err)
+	}
+	return p.includeTaxRat(r, desc)
+}
+
+// includeTaxRat includes a tax in the price using an exact percentage. The
+// tax is taken out of the current net, so the taxes already applied are
+// charged on it. It's recorded in front of them as a tax on the net plus every
+// earlier tax, which is only correct if they're also charged on every earlier
+// tax.
+func (p *Price) includeTaxRat(percent *big.Rat, desc string) error {
+	for _, l := range p.taxes.detail {
+		if l.Tax.Base == TaxOnNet || l.Tax.Base == TaxCompound {
+			return fmt.Errorf("failed to include tax '%s', '%s' is not charged on it, use IncludeTaxes instead", desc, l.Tax.Description)
+		}
+	}
+	divisor := new(big.Rat).Add(percent, big.NewRat(100, 1))
+	if divisor.Sign() == 0 {
+		return fmt.Errorf("failed to include tax '%s', %w", desc, ErrDivideByZero)
+	}
+	net := p.Net()
+	untaxed, err := net.mulRat(divisor.Quo(big.NewRat(100, 1), divisor))
+	if err != nil {
+		return fmt.Errorf("failed to include tax '%s', %w", desc, err)
+	}
+	p.taxes = p.taxes.prepend(Tax{Description: desc, Base: TaxOnGross, Mode: TaxInclusive, Rate: percent}, net.Sub(untaxed))
+	return nil
+}
+
+// AddTaxes adds taxes to the price in order. Each tax is calculated on the
+// net plus the earlier taxes given by its base, rounded using the gross
+// price's rounding function and added to the gross price. Taxes already
+// applied to the price count as earlier taxes. The taxes are recorded as
+// exclusive. The price is left unmodified if an error is returned.
+func (p *Price) AddTaxes(ts ...Tax) error {
+	ts = withMode(ts, TaxExclusive)
+	if err := p.checkTaxes(ts); err != nil {
+		return fmt.Errorf("failed to add taxes, %w", err)
+	}
+	amounts, err := p.calculateTaxes(new(big.Rat).SetInt64(p.Net().value), ts)
+	if err != nil {
+		return fmt.Errorf("failed to add taxes, %w", err)
+	}
+	result := *p
+	for i, t := range ts {
+		if result.gross, err = result.gross.AddE(amounts[i]); err != nil {
+			return fmt.Errorf("failed to add tax '%s', %w", t.Description, err)
+		}
+		if result.taxes, err = result.taxes.addE(t, amounts[i]); err != nil {
+			return fmt.Errorf("failed to add tax '%s', %w", t.Description, err)
+		}
+	}
+	*p = result
+	return nil
+}
+
+// IncludeTaxes adds taxes to the price in order. This implies the taxes are
+// already included in the gross price. The exact net is found so that it plus
+// the taxes calculated from it equals the current net, then each tax is
+// calculated the same way as AddTaxes. Any rounding difference is kept in the
+// net. The taxes are recorded as inclusive. The price is left unmodified if an
+// error is returned.
+func (p *Price) IncludeTaxes(ts ...Tax) error {
+	ts = withMode(ts, TaxInclusive)
+	if err := p.checkTaxes(ts); err != nil {
+		return fmt.Errorf("failed to include taxes, %w", err)
+	}
+	net, err := p.solveNet(ts)
+	if err != nil {
+		return fmt.Errorf("failed to include taxes, %w", err)
+	}
+	amounts, err := p.calculateTaxes(net, ts)
+	if err != nil {
+		return fmt.Errorf("failed to include taxes, %w", err)
+	}
+	result := *p
+	for i, t := range ts {
+		if result.taxes, err = result.taxes.addE(t, amounts[i]); err != nil {
+			return fmt.Errorf("failed to include tax '%s', %w", t.Description, err)
+		}
+	}
+	*p = result
+	return nil
+}
+
+// ApplyTaxes adds taxes to the price according to their mode. Inclusive taxes
+// are included first using IncludeTaxes, then exclusive taxes are added using
+// AddTaxes. The price is left unmodified if an error is returned.
+func (p *Price) ApplyTaxes(ts ...Tax) error {
+	var inclusive, exclusive []Tax
+	for _, t := range ts {
+		if t.Mode == TaxInclusive {
+			inclusive = append(inclusive, t)
+		} else {
+			exclusive = append(exclusive, t)
+		}
+	}
+	result := *p
+	if err := result.IncludeTaxes(inclusive...); err != nil {
+		return err
+	}
+	if err := result.AddTaxes(exclusive...); err != nil {
+		return err
+	}
+	*p = result
+	return nil
+}
+
+// Taxes returns the definitions of the taxes applied to the price in the order
+// they were applied.
+func (p Price) Taxes() []Tax {
+	ts := make([]Tax, len(p.taxes.detail))
+	for i, l := range p.taxes.detail {
+		ts[i] = l.Tax.clone()
+	}
+	return ts
+}
+
+// TaxLines returns the amount of each tax applied to the price in the order
+// they were applied.
+func (p Price) TaxLines() []TaxLine {
+	lines := make([]TaxLine, len(p.taxes.detail))
+	for i, l := range p.taxes.detail {
+		lines[i] = TaxLine{Tax: l.Tax.clone(), Amount: l.Amount}
+	}
+	return lines
+}
+
+// TaxBases returns the amount each tax applied to the price is charged on in
+// the order they were applied, i.e. the net plus the earlier taxes given by
+// its base. Per unit taxes aren't charged on an amount so their base is zero.
+func (p Price) TaxBases() ([]Money, error) {
+	net := p.Net()
+	bases := make([]Money, len(p.taxes.detail))
+	for i, l := range p.taxes.detail {
+		if l.Tax.Base == TaxPerUnit {
+			bases[i] = p.gross.Clone(0)
+			continue
+		}
+		base := net
+		for _, e := range p.taxes.detail[:i] {
+			if !l.Tax.appliesTo(e.Tax) {
+				continue
+			}
+			var err error
+			if base, err = base.AddE(e.Amount); err != nil {
+				return nil, fmt.Errorf("failed to calculate the base of tax '%s', %w", l.Tax.Description, err)
+			}
+		}
+		bases[i] = base
+	}
+	return bases, nil
+}
+
+// ApplyDiscounts takes discounts off the price in order. Each discount is
+// calculated on the net or the gross depending on its timing, and the taxes
+// already applied to the price are recalculated on the discounted amount, so
+// the tax base goes down with the price. Per unit taxes aren't changed.
+// Discounts that take nothing off, e.g. because the price is below their
+// minimum, aren't recorded. The price is left unmodified if an error is
+// returned.
+func (p *Price) ApplyDiscounts(ds ...Discount) error {
+	result := *p
+	for _, d := range ds {
+		if err := d.check(result.gross); err != nil {
+			return fmt.Errorf("failed to apply discounts, %w", err)
+		}
+		amount, err := d.calculate(result.discountBase(d.Timing))
+		if err != nil {
+			return fmt.Errorf("failed to apply discounts, %w", err)
+		}
+		if err := result.discount(d, amount); err != nil {
+			return fmt.Errorf("failed to apply discounts, %w", err)
+		}
+	}
+	*p = result
+	return nil
+}
+
+// Discount returns the total amount taken off the price by discounts.
+func (p Price) Discount() Money {
+	return p.discounts.total(p.gross)
+}
+
+// Discounts returns the amount of each discount taken off the price in the
+// order they were applied.
+func (p Price) Discounts() []DiscountLine {
+	lines := make([]DiscountLine, len(p.discounts))
+	for i, l := range p.discounts {
+		lines[i] = DiscountLine{Discount: l.Discount.clone(), Amount: l.Amount}
+	}
+	return lines
+}
+
+// discountBase returns the amount a discount with the passed timing is
+// calculated on.
+func (p Price) discountBase(timing DiscountTiming) Money {
+	if timing == DiscountAfterTax {
+		return p.gross
+	}
+	return p.Net()
+}
+
+// discount takes the passed amount off the price and recalculates its taxes.
+// Before tax the amount comes off the net and the taxes are added to it again.
+// After tax it comes off the gross and the taxes are included in it again.
+// Discounts that take nothing off aren't recorded.
+func (p *Price) discount(d Discount, amount Money) error {
+	if amount.IsZero() {
+		return nil
+	}
+
+	// Per unit taxes keep their current amount, which may be for several
+	// units.
+	ts := make([]Tax, len(p.taxes.detail))
+	for i, l := range p.taxes.detail {
+		ts[i] = l.Tax
+		if l.Tax.Base == TaxPerUnit {
+			ts[i].Amount = l.Amount
+		}
+	}
+
+	result := Price{
+		taxes:     taxes{total: p.gross.Clone(0), detail: make(detail, 0, len(ts))},
+		discounts: p.discounts,
+	}
+	var err error
+	var net *big.Rat
+	if d.Timing == DiscountAfterTax {
+		if result.gross, err = p.gross.SubE(amount); err != nil {
+			return fmt.Errorf("failed to apply discount '%s', %w", d.Description, err)
+		}
+		if net, err = result.solveNet(ts); err != nil {
+			return fmt.Errorf("failed to apply discount '%s', %w", d.Description, err)
+		}
+	} else {
+		if result.gross, err = p.Net().SubE(amount); err != nil {
+			return fmt.Errorf("failed to apply discount '%s', %w", d.Description, err)
+		}
+		net = new(big.Rat).SetInt64(result.gross.value)
+	}
+
+	amounts, err := result.calculateTaxes(net, ts)
+	if err != nil {
+		return fmt.Errorf("failed to apply discount '%s', %w", d.Description, err)
+	}
+	for i, l := range p.taxes.detail {
+		if d.Timing == DiscountBeforeTax {
+			if result.gross, err = result.gross.AddE(amounts[i]); err != nil {
+				return fmt.Errorf("failed to apply discount '%s', %w", d.Description, err)
+			}
+		}
+		if result.taxes, err = result.taxes.addE(l.Tax, amounts[i]); err != nil {
+			return fmt.Errorf("failed to apply discount '%s', %w", d.Description, err)
+		}
+	}
+
+	if result.discounts, err = result.discounts.addE(d, amount); err != nil {
+		return fmt.Errorf("failed to apply discount '%s', %w", d.Description, err)
+	}
+	*p = result
+	return nil
+}
+
+// checkTaxes validates taxes before they're applied to the price. Compound
+// taxes must only name taxes that were applied before them.
+func (p Price) checkTaxes(ts []Tax) error {
+	for i, t := range ts {
+		if err := t.validate(); err != nil {
+			return err
+		}
+		if t.Base == TaxPerUnit {
+			if err := checkSameMoneyCurrency(p.gross, t.Amount); err != nil {
+				return fmt.Errorf("invalid tax '%s', %w", t.Description, err)
+			}
+		}
+		for _, name := range t.On {
+			on := Tax{Base: TaxCompound, On: []string{name}}
+			ok := slices.IndexFunc(p.taxes.detail, func(l TaxLine) bool { return on.names(l.Tax) }) >= 0
+			for j := 0; j < i && !ok; j++ {
+				ok = on.names(ts[j])
+			}
+			if !ok {
+				return fmt.Errorf("invalid tax '%s', '%s' is not an earlier tax", t.Description, name)
+			}
+		}
+	}
+	return nil
+}
+
+// calculateTaxes returns the amount of each tax for the passed exact net.
+// A tax charged on earlier taxes uses their rounded amounts.
+func (p Price) calculateTaxes(net *big.Rat, ts []Tax) ([]Money, error) {
+	amounts := make([]Money, len(ts))
+	for i, t := range ts {
+		if t.Base == TaxPerUnit {
+			amounts[i] = p.gross.Clone(t.Amount.value)
+			continue
+		}
+		base := new(big.Rat).Set(net)
+		for _, l := range p.taxes.detail {
+			if t.appliesTo(l.Tax) {
+				base.Add(base, new(big.Rat).SetInt64(l.Amount.value))
+			}
+		}
+		for j := 0; j < i; j++ {
+			if t.appliesTo(ts[j]) {
+				base.Add(base, new(big.Rat).SetInt64(amounts[j].value))
+			}
+		}
+		base.Mul(base, t.Rate).Quo(base, big.NewRat(100, 1))
+		value := roundRat(base, p.gross.round)
+		if !value.IsInt64() {
+			return nil, fmt.Errorf("failed to calculate tax '%s', %w", t.Description, ErrOverflow)
+		}
+		amounts[i] = p.gross.Clone(value.Int64())
+	}
+	return amounts, nil
+}
+
+// solveNet returns the exact net that, plus the taxes calculated from it,
+// equals the current net. Each tax is a linear function of the net, a*net+b,
+// so the taxes are summed as coefficients before solving.
+func (p Price) solveNet(ts []Tax) (*big.Rat, error) {
+	a := make([]*big.Rat, len(ts))
+	b := make([]*big.Rat, len(ts))
+	sumA := big.NewRat(1, 1)
+	sumB := new(big.Rat)
+	for i, t := range ts {
+		if t.Base == TaxPerUnit {
+			a[i] = new(big.Rat)
+			b[i] = new(big.Rat).SetInt64(t.Amount.value)
+		} else {
+			a[i] = big.NewRat(1, 1)
+			b[i] = new(big.Rat)
+			for _, l := range p.taxes.detail {
+				if t.appliesTo(l.Tax) {
+					b[i].Add(b[i], new(big.Rat).SetInt64(l.Amount.value))
+				}
+			}
+			for j := 0; j < i; j++ {
+				if t.appliesTo(ts[j]) {
+					a[i].Add(a[i], a[j])
+					b[i].Add(b[i], b[j])
+				}
+			}
+			k := new(big.Rat).Quo(t.Rate, big.NewRat(100, 1))
+			a[i].Mul(a[i], k)
+			b[i].Mul(b[i], k)
+		}
+		sumA.Add(sumA, a[i])
+		sumB.Add(sumB, b[i])
+	}
+	if sumA.Sign() == 0 {
+		return nil, ErrDivideByZero
+	}
+	net := new(big.Rat).SetInt64(p.Net().value)
+	return net.Sub(net, sumB).Quo(net, sumA), nil
+}
+
+// withMode returns copies of the taxes set to the passed mode.
+func withMode(ts []Tax, mode TaxMode) []Tax {
+	result := make([]Tax, len(ts))
+	for i, t := range ts {
+		result[i] = t.clone()
+		result[i].Mode = mode
+	}
+	return result
 }
 
 // IsoCode returns the ISO 4217 currency code.
 func (p Price) IsoCode() string {
-	return p.gross.format.code
+	return p.gross.currency.Code
 }
 
 // Gross returns the gross monetary value of the price.
@@ -148,8 +572,11 @@ func (p Price) Tax() Money {
 // Add is an arithmetic operator.
 func (p Price) Add(v Price) Price {
 	p.gross = p.gross.Add(v.gross)
-	for k, v := range v.taxes.detail {
-		p.taxes = p.taxes.add(k, v)
+	for _, l := range v.taxes.detail {
+		p.taxes = p.taxes.add(l.Tax, l.Amount)
+	}
+	for _, l := range v.discounts {
+		p.discounts = p.discounts.add(l.Discount, l.Amount)
 	}
 	return p
 }
@@ -158,10 +585,110 @@ func (p Price) Add(v Price) Price {
 func (p Price) Mul(n int64) Price {
 	p.gross = p.gross.Mul(n)
 	p.taxes = p.taxes.mul(n)
+	p.discounts = p.discounts.mul(n)
+	return p
+}
+
+// Sub is an arithmetic operator. Each tax is subtracted from the tax with the
+// same ID or, if it has no ID, the same jurisdiction and label. Taxes that only
+// share a description are kept separate.
+func (p Price) Sub(v Price) Price {
+	p.gross = p.gross.Sub(v.gross)
+	for _, l := range v.taxes.detail {
+		p.taxes = p.taxes.add(l.Tax, l.Amount.FlipSign())
+	}
+	for _, l := range v.discounts {
+		p.discounts = p.discounts.add(l.Discount, l.Amount.FlipSign())
+	}
+	return p
+}
+
+// Neg returns the price with the gross, every tax and every discount negated,
+// e.g. to produce a refund.
+func (p Price) Neg() Price {
+	p.gross = p.gross.FlipSign()
+	p.taxes = p.taxes.neg()
+	p.discounts = p.discounts.neg()
 	return p
 }
 
-// MarshalJSON is an implementation of json.Marshaller.
+// Div is an arithmetic operator. The gross and each tax are divided and
+// rounded separately using the gross price's rounding function, so the result
+// multiplied by n may not equal the original price. If you need to divide a
+// price with lossless precision, use the Split or Allocate functions instead.
+func (p Price) Div(n int64) Price {
+	result, err := p.DivE(n)
+	if errors.Is(err, ErrDivideByZero) {
+		panic("Failed to divide price by zero")
+	} else if err != nil {
+		panic("Failed to divide price, integer overflow")
+	}
+	return result
+}
+
+// Split returns a slice containing prices split as evenly as possible by 'n'
+// times. The gross and each tax are split separately, so the gross, net and
+// every tax of the parts add up exactly to those of the original price.
+func (p Price) Split(n int64) []Price {
+	s, err := p.SplitE(n)
+	if err != nil {
+		panic("Failed to split price by zero")
+	}
+	return s
+}
+
+// Allocate returns a slice containing prices split according to the passed
+// ratios. The gross and each tax are allocated separately, so the gross, net
+// and every tax of the parts add up exactly to those of the original price.
+func (p Price) Allocate(ratios ...int64) []Price {
+	s, err := p.AllocateE(ratios...)
+	if errors.Is(err, ErrOverflow) {
+		panic("Failed to allocate price, integer overflow")
+	} else if err != nil {
+		panic("Failed to allocate price, no ratios passed")
+	}
+	return s
+}
+
+// parts divides the gross, each tax and each discount of the price using the
+// passed function and assembles the results into prices. The function must
+// return the same number of parts for every value.
+func (p Price) parts(f func(Money) ([]Money, error)) ([]Price, error) {
+	gross, err := f(p.gross)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]Price, len(gross))
+	for i, g := range gross {
+		parts[i] = Price{
+			gross: g,
+			taxes: taxes{total: g.Clone(0), detail: make(detail, 0, len(p.taxes.detail))},
+		}
+	}
+	for _, l := range p.taxes.detail {
+		s, err := f(l.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to divide tax '%s', %w", l.Tax.Description, err)
+		}
+		for i := range parts {
+			parts[i].taxes = parts[i].taxes.add(l.Tax, s[i])
+		}
+	}
+	for _, l := range p.discounts {
+		s, err := f(l.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to divide discount '%s', %w", l.Discount.Description, err)
+		}
+		for i := range parts {
+			parts[i].discounts = parts[i].discounts.add(l.Discount, s[i])
+		}
+	}
+	return parts, nil
+}
+
+// MarshalJSON is an implementation of json.Marshaller. The rounding mode is
+// included unless it's RoundHalfUp and the discounts are only included if any
+// were applied.
 func (p Price) MarshalJSON() ([]byte, error) {
 	tax, err := json.Marshal(p.taxes)
 	if err != nil {
@@ -169,14 +696,88 @@ func (p Price) MarshalJSON() ([]byte, error) {
 	}
 
 	json := fmt.Sprintf(
-		`{"currency": "%s", "gross": "%s", "net": "%s", "tax": %s}`,
+		`{"currency": "%s", "gross": "%s", "net": "%s", "tax": %s%s`,
 		p.IsoCode(),
 		p.Gross(),
 		p.Net(),
 		tax,
+		p.gross.round.jsonField(),
 	)
 
-	return []byte(json), nil
+	if len(p.discounts) > 0 {
+		discount, err := p.discounts.marshal(p.gross)
+		if err != nil {
+			return []byte{}, err
+		}
+		json += fmt.Sprintf(`, "discount": %s`, discount)
+	}
+
+	return []byte(json + "}"), nil
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. It accepts the output
+// of MarshalJSON and rebuilds each named tax from the detail. If a net value is
+// present it must equal the gross minus the tax total. If no rounding mode is
+// present RoundHalfUp is used, because MarshalJSON omits it.
+func (p *Price) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Currency string          `json:"currency"`
+		Gross    json.RawMessage `json:"gross"`
+		Net      json.RawMessage `json:"net"`
+		Tax      json.RawMessage `json:"tax"`
+		Discount json.RawMessage `json:"discount"`
+		Rounding *RoundingMode   `json:"rounding"`
+	}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal price, %w", err)
+	}
+
+	if len(v.Gross) == 0 {
+		return fmt.Errorf("failed to unmarshal price, no gross specified")
+	}
+
+	round := RoundingMode(RoundHalfUp)
+	if v.Rounding != nil {
+		round = *v.Rounding
+	}
+
+	price, err := PriceFromSubunits(v.Currency, 0, round)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal price, %w", err)
+	}
+
+	if err := json.Unmarshal(v.Gross, &price.gross); err != nil {
+		return fmt.Errorf("failed to unmarshal price gross, %w", err)
+	}
+	if price.gross.currency.Code != v.Currency {
+		return fmt.Errorf("failed to unmarshal price, gross currency '%s' does not match '%s'", price.gross.currency.Code, v.Currency)
+	}
+
+	if len(v.Tax) > 0 {
+		if err := json.Unmarshal(v.Tax, &price.taxes); err != nil {
+			return fmt.Errorf("failed to unmarshal price, %w", err)
+		}
+	}
+
+	if len(v.Discount) > 0 {
+		if err := price.discounts.unmarshal(v.Discount, price.gross.Clone(0)); err != nil {
+			return fmt.Errorf("failed to unmarshal price, %w", err)
+		}
+	}
+
+	if len(v.Net) > 0 {
+		net := price.gross.Clone(0)
+		if err := json.Unmarshal(v.Net, &net); err != nil {
+			return fmt.Errorf("failed to unmarshal price net, %w", err)
+		}
+		if net.currency.Code != v.Currency || net.value != price.Net().value {
+			return fmt.Errorf("failed to unmarshal price, net %s does not equal gross %s minus tax %s", net, price.gross, price.taxes.total)
+		}
+	}
+
+	*p = price
+	return nil
 }
 
 // String is an implementation of fmt.Stringer and returns the string
@@ -185,6 +786,12 @@ func (p Price) String() string {
 	return p.gross.String()
 }
 
+// StringStyle returns the string formatted representation of the gross
+// monetary value, presenting negative values using the passed style.
+func (p Price) StringStyle(s NegativeStyle) string {
+	return p.gross.StringStyle(s)
+}
+
 // StringNoSymbol returns the string formatted representation of the price
 // value without a currency symbol.
 func (p Price) StringNoSymbol() string {
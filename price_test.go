@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -209,10 +210,10 @@ func TestPriceJsonMarshalling(t *testing.T) {
 	}
 
 	bytes, _ := json.Marshal(price)
-	assertJSON(t, bytes, `{"currency":"GBP","gross":"£10.99","net":"£9.16","tax":{"total":"£1.83","detail":[{"amount":"£1.83","description":"VAT"}]}}`)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£10.99","net":"£9.16","tax":{"total":"£1.83","detail":[{"description":"VAT","label":"VAT 20%","base":"gross","mode":"inclusive","rate":"20","amount":"£1.83"}]}}`)
 
 	bytes, _ = json.Marshal(resp)
-	assertJSON(t, bytes, `{"name":"Widget","price":{"currency":"GBP","gross":"£10.99","net":"£9.16","tax":{"total":"£1.83","detail":[{"amount":"£1.83","description":"VAT"}]}}}`)
+	assertJSON(t, bytes, `{"name":"Widget","price":{"currency":"GBP","gross":"£10.99","net":"£9.16","tax":{"total":"£1.83","detail":[{"description":"VAT","label":"VAT 20%","base":"gross","mode":"inclusive","rate":"20","amount":"£1.83"}]}}}`)
 }
 
 func TestPriceString(t *testing.T) {
@@ -279,7 +280,7 @@ func TestAddPriceAndImmutability(t *testing.T) {
 	assertMoneyValue(t, p1.Tax(), 432)
 
 	bytes, _ := json.Marshal(p1)
-	assertJSON(t, bytes, `{"currency":"GBP","gross":"£25.15","net":"£20.83","tax":{"total":"£4.32","detail":[{"amount":"£1.20","description":"Small order"},{"amount":"£3.12","description":"VAT"}]}}`)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£25.15","net":"£20.83","tax":{"total":"£4.32","detail":[{"description":"VAT","label":"VAT 15%","base":"gross","mode":"exclusive","rate":"15","amount":"£3.12"},{"description":"Small order","label":"Small order 5%","base":"gross","mode":"exclusive","rate":"5","amount":"£1.20"}]}}`)
 
 	p2, _ := PriceFromSubunits("GBP", 1545, nil)
 	p2.AddTaxPercent(15, "VAT")
@@ -290,14 +291,14 @@ func TestAddPriceAndImmutability(t *testing.T) {
 	assertMoneyValue(t, p3.Tax(), 664)
 
 	bytes, _ = json.Marshal(p3)
-	assertJSON(t, bytes, `{"currency":"GBP","gross":"£42.92","net":"£36.28","tax":{"total":"£6.64","detail":[{"amount":"£1.20","description":"Small order"},{"amount":"£5.44","description":"VAT"}]}}`)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£42.92","net":"£36.28","tax":{"total":"£6.64","detail":[{"description":"VAT","label":"VAT 15%","base":"gross","mode":"exclusive","rate":"15","amount":"£5.44"},{"description":"Small order","label":"Small order 5%","base":"gross","mode":"exclusive","rate":"5","amount":"£1.20"}]}}`)
 
 	assertMoneyValue(t, p1.Gross(), 2515)
 	assertMoneyValue(t, p1.Net(), 2083)
 	assertMoneyValue(t, p1.Tax(), 432)
 
 	bytes, _ = json.Marshal(p1)
-	assertJSON(t, bytes, `{"currency":"GBP","gross":"£25.15","net":"£20.83","tax":{"total":"£4.32","detail":[{"amount":"£1.20","description":"Small order"},{"amount":"£3.12","description":"VAT"}]}}`)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£25.15","net":"£20.83","tax":{"total":"£4.32","detail":[{"description":"VAT","label":"VAT 15%","base":"gross","mode":"exclusive","rate":"15","amount":"£3.12"},{"description":"Small order","label":"Small order 5%","base":"gross","mode":"exclusive","rate":"5","amount":"£1.20"}]}}`)
 }
 
 func TestMulPriceAndImmutability(t *testing.T) {
@@ -310,7 +311,7 @@ func TestMulPriceAndImmutability(t *testing.T) {
 	assertMoneyValue(t, p1.Tax(), 432)
 
 	bytes, _ := json.Marshal(p1)
-	assertJSON(t, bytes, `{"currency":"GBP","gross":"£25.15","net":"£20.83","tax":{"total":"£4.32","detail":[{"amount":"£1.20","description":"Small order"},{"amount":"£3.12","description":"VAT"}]}}`)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£25.15","net":"£20.83","tax":{"total":"£4.32","detail":[{"description":"VAT","label":"VAT 15%","base":"gross","mode":"exclusive","rate":"15","amount":"£3.12"},{"description":"Small order","label":"Small order 5%","base":"gross","mode":"exclusive","rate":"5","amount":"£1.20"}]}}`)
 
 	p2 := p1.Mul(3)
 	assertMoneyValue(t, p2.Gross(), 7545)
@@ -318,12 +319,382 @@ func TestMulPriceAndImmutability(t *testing.T) {
 	assertMoneyValue(t, p2.Tax(), 1296)
 
 	bytes, _ = json.Marshal(p2)
-	assertJSON(t, bytes, `{"currency":"GBP","gross":"£75.45","net":"£62.49","tax":{"total":"£12.96","detail":[{"amount":"£3.60","description":"Small order"},{"amount":"£9.36","description":"VAT"}]}}`)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£75.45","net":"£62.49","tax":{"total":"£12.96","detail":[{"description":"VAT","label":"VAT 15%","base":"gross","mode":"exclusive","rate":"15","amount":"£9.36"},{"description":"Small order","label":"Small order 5%","base":"gross","mode":"exclusive","rate":"5","amount":"£3.60"}]}}`)
 
 	assertMoneyValue(t, p1.Gross(), 2515)
 	assertMoneyValue(t, p1.Net(), 2083)
 	assertMoneyValue(t, p1.Tax(), 432)
 
 	bytes, _ = json.Marshal(p1)
-	assertJSON(t, bytes, `{"currency":"GBP","gross":"£25.15","net":"£20.83","tax":{"total":"£4.32","detail":[{"amount":"£1.20","description":"Small order"},{"amount":"£3.12","description":"VAT"}]}}`)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£25.15","net":"£20.83","tax":{"total":"£4.32","detail":[{"description":"VAT","label":"VAT 15%","base":"gross","mode":"exclusive","rate":"15","amount":"£3.12"},{"description":"Small order","label":"Small order 5%","base":"gross","mode":"exclusive","rate":"5","amount":"£1.20"}]}}`)
+}
+
+func TestSubPriceAndImmutability(t *testing.T) {
+	p1, _ := PriceFromSubunits("GBP", 2083, nil)
+	p1.AddTaxPercent(15, "VAT")
+	p1.AddTaxPercent(5, "Small order")
+
+	p2, _ := PriceFromSubunits("GBP", 1000, nil)
+	p2.AddTaxPercent(15, "VAT")
+
+	p3 := p1.Sub(p2)
+	assertMoneyValue(t, p3.Gross(), 1365)
+	assertMoneyValue(t, p3.Net(), 1083)
+	assertMoneyValue(t, p3.Tax(), 282)
+
+	bytes, _ := json.Marshal(p3)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£13.65","net":"£10.83","tax":{"total":"£2.82","detail":[{"description":"VAT","label":"VAT 15%","base":"gross","mode":"exclusive","rate":"15","amount":"£1.62"},{"description":"Small order","label":"Small order 5%","base":"gross","mode":"exclusive","rate":"5","amount":"£1.20"}]}}`)
+
+	assertMoneyValue(t, p1.Gross(), 2515)
+	assertMoneyValue(t, p1.Tax(), 432)
+	assertMoneyValue(t, p3.Add(p2).Gross(), 2515)
+}
+
+func TestNegPrice(t *testing.T) {
+	p1, _ := PriceFromSubunits("GBP", 2083, nil)
+	p1.AddTaxPercent(15, "VAT")
+
+	p2 := p1.Neg()
+	assertMoneyValue(t, p2.Gross(), -2395)
+	assertMoneyValue(t, p2.Net(), -2083)
+	assertMoneyValue(t, p2.Tax(), -312)
+
+	bytes, _ := json.Marshal(p2)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£-23.95","net":"£-20.83","tax":{"total":"£-3.12","detail":[{"description":"VAT","label":"VAT 15%","base":"gross","mode":"exclusive","rate":"15","amount":"£-3.12"}]}}`)
+
+	assertMoneyValue(t, p1.Gross(), 2395)
+	assert(t, p1.Add(p2).Gross().IsZero())
+	assert(t, p1.Add(p2).Tax().IsZero())
+}
+
+func TestDivPrice(t *testing.T) {
+	p1, _ := PriceFromSubunits("GBP", 1000, nil)
+	p1.IncludeTaxPercent(20, "VAT")
+	p1.IncludeTaxPercent(5, "Levy")
+
+	p2 := p1.Div(3)
+	assertMoneyValue(t, p2.Gross(), 333)
+	assertMoneyValue(t, p2.Tax(), 69)
+	assertMoneyValue(t, p2.Net(), 264)
+
+	bytes, _ := json.Marshal(p2)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£3.33","net":"£2.64","tax":{"total":"£0.69","detail":[{"description":"Levy","label":"Levy 5%","base":"gross","mode":"inclusive","rate":"5","amount":"£0.13"},{"description":"VAT","label":"VAT 20%","base":"gross","mode":"inclusive","rate":"20","amount":"£0.56"}]}}`)
+
+	assertMoneyValue(t, p1.Gross(), 1000)
+}
+
+func TestDivPriceByZero(t *testing.T) {
+	defer assertPanic(t)
+	p, _ := PriceFromSubunits("GBP", 1000, nil)
+	p.Div(0)
+}
+
+func TestSplitPrice(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 1000, nil)
+	p.IncludeTaxPercent(20, "VAT")
+	p.IncludeTaxPercent(5, "Levy")
+
+	parts := p.Split(3)
+	assert(t, len(parts) == 3)
+	assertPriceString(t, parts[0], "GBP", "£3.34")
+	assertPriceString(t, parts[1], "GBP", "£3.33")
+	assertPriceString(t, parts[2], "GBP", "£3.33")
+	assertPriceParts(t, p, parts)
+}
+
+func TestSplitPriceByZero(t *testing.T) {
+	defer assertPanic(t)
+	p, _ := PriceFromSubunits("GBP", 1000, nil)
+	p.Split(0)
+}
+
+func TestAllocatePrice(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 1001, nil)
+	p.AddTaxPercent(20, "VAT")
+	p.AddTaxPercent(7.5, "Delivery")
+
+	parts := p.Allocate(1, 2, 4)
+	assert(t, len(parts) == 3)
+	assertPriceString(t, parts[0], "GBP", "£1.85")
+	assertPriceString(t, parts[1], "GBP", "£3.69")
+	assertPriceString(t, parts[2], "GBP", "£7.37")
+	assertPriceParts(t, p, parts)
+
+	n := p.Neg()
+	assertPriceParts(t, n, n.Allocate(3, 3, 1))
+}
+
+func TestAllocatePriceNoRatios(t *testing.T) {
+	defer assertPanic(t)
+	p, _ := PriceFromSubunits("GBP", 1000, nil)
+	p.Allocate()
+}
+
+// assertPriceParts checks the gross, net and every tax of the parts add up to
+// those of the original price.
+func assertPriceParts(t *testing.T, p Price, parts []Price) {
+	t.Helper()
+	sum, _ := PriceFromSubunits(p.IsoCode(), 0, nil)
+	for _, part := range parts {
+		assertMoneyValue(t, part.Net(), part.Gross().Value()-part.Tax().Value())
+		sum = sum.Add(part)
+	}
+	assertMoneyValue(t, sum.Gross(), p.Gross().Value())
+	assertMoneyValue(t, sum.Net(), p.Net().Value())
+	assertMoneyValue(t, sum.Tax(), p.Tax().Value())
+	assert(t, len(sum.taxes.detail) == len(p.taxes.detail))
+	for i, l := range p.taxes.detail {
+		assert(t, sum.taxes.detail[i].Tax.key() == l.Tax.key())
+		assertMoneyValue(t, sum.taxes.detail[i].Amount, l.Amount.Value())
+	}
+}
+
+func TestPriceJsonUnmarshalling(t *testing.T) {
+	type Response struct {
+		Name  string `json:"name"`
+		Price Price  `json:"price"`
+	}
+
+	var resp Response
+	err := json.Unmarshal([]byte(`{"name":"Widget","price":{"currency":"GBP","gross":"£42.92","net":"£36.28","tax":{"total":"£6.64","detail":[{"amount":"£1.20","description":"Small order"},{"amount":"£5.44","description":"VAT"}]}}}`), &resp)
+	if err != nil {
+		t.Errorf("Price failed to unmarshal: %s", err)
+	}
+
+	assertPriceString(t, resp.Price, "GBP", "£42.92")
+	assertMoneyValue(t, resp.Price.Gross(), 4292)
+	assertMoneyValue(t, resp.Price.Net(), 3628)
+	assertMoneyValue(t, resp.Price.Tax(), 664)
+	assertMoneyValue(t, taxAmount(resp.Price.taxes.detail, "VAT"), 544)
+	assertMoneyValue(t, taxAmount(resp.Price.taxes.detail, "Small order"), 120)
+}
+
+func TestPriceJsonRoundTrip(t *testing.T) {
+	p1, _ := PriceFromSubunits("GBP", 2083, RoundDown)
+	p1.AddTaxPercent(15, "VAT")
+	p1.AddTaxPercent(5, "Small order")
+
+	bytes, _ := json.Marshal(p1)
+
+	p2, _ := PriceFromSubunits("GBP", 0, RoundDown)
+	err := json.Unmarshal(bytes, &p2)
+	if err != nil {
+		t.Errorf("Price failed to unmarshal: %s", err)
+	}
+
+	assert(t, p1.Gross().Eq(p2.Gross()))
+	assert(t, p1.Net().Eq(p2.Net()))
+	assert(t, p1.Tax().Eq(p2.Tax()))
+
+	result, _ := json.Marshal(p2)
+	assertJSON(t, result, string(bytes))
+
+	p1.AddTaxPercent(10, "Service")
+	p2.AddTaxPercent(10, "Service")
+	assert(t, p1.Gross().Eq(p2.Gross()))
+
+	// RoundHalfUp isn't encoded but isn't replaced by the receiver's mode.
+	p3, _ := PriceFromSubunits("GBP", 1000, RoundHalfUp)
+	bytes, _ = json.Marshal(p3)
+	p4, _ := PriceFromSubunits("GBP", 0, RoundDown)
+	err = json.Unmarshal(bytes, &p4)
+	assert(t, err == nil)
+	assert(t, p4.Gross().Rounding().String() == "HalfUp")
+}
+
+func TestPriceJsonUnmarshallingErrors(t *testing.T) {
+	var p Price
+	for _, str := range []string{
+		`{"currency":"XXX","gross":"£10.99"}`,
+		`{"currency":"GBP"}`,
+		`{"currency":"GBP","gross":{"currency":"EUR","amount":"€10.99"}}`,
+		`{"currency":"GBP","gross":"£10.99","net":"£9.15","tax":{"total":"£1.83","detail":[{"amount":"£1.83","description":"VAT"}]}}`,
+		`{"currency":"GBP","gross":"£10.99","net":"£9.16","tax":{"total":"£1.84","detail":[{"amount":"£1.83","description":"VAT"}]}}`,
+		`{"currency":"GBP","gross":"£10.99","tax":{"detail":[{"amount":{"currency":"EUR","amount":"€1.83"},"description":"VAT"}]}}`,
+		`{"currency":"GBP","gross":"£10.99","tax":{"detail":{}}}`,
+	} {
+		if err := json.Unmarshal([]byte(str), &p); err == nil {
+			t.Errorf("Price failed to error when unmarshalling %s", str)
+		}
+	}
+}
+
+func TestAddParallelTaxes(t *testing.T) {
+	gst, _ := NetTax("GST", "5")
+	pst, _ := NetTax("PST", "7")
+
+	p, _ := PriceFromSubunits("CAD", 10000, nil)
+	assert(t, p.AddTaxes(gst, pst) == nil)
+	assertMoneyValue(t, p.Gross(), 11200)
+	assertMoneyValue(t, p.Net(), 10000)
+	assertMoneyValue(t, p.Tax(), 1200)
+
+	bytes, _ := json.Marshal(p)
+	assertJSON(t, bytes, `{"currency":"CAD","gross":"$112.00","net":"$100.00","tax":{"total":"$12.00","detail":[{"description":"GST","label":"GST 5%","base":"net","mode":"exclusive","rate":"5","amount":"$5.00"},{"description":"PST","label":"PST 7%","base":"net","mode":"exclusive","rate":"7","amount":"$7.00"}]}}`)
+}
+
+func TestAddCompoundTaxes(t *testing.T) {
+	gst, _ := NetTax("GST", "5")
+	qst, _ := CompoundTax("QST", "9.975", "GST")
+
+	p, _ := PriceFromSubunits("CAD", 10000, nil)
+	assert(t, p.AddTaxes(gst, qst) == nil)
+	assertMoneyValue(t, p.Gross(), 11547)
+	assertMoneyValue(t, p.Tax(), 1547)
+
+	duty, _ := NetTax("Duty", "12")
+	vat, _ := CompoundTax("Import VAT", "20", "Duty")
+	levy, _ := NetTax("Levy", "1")
+
+	p, _ = PriceFromSubunits("GBP", 5000, nil)
+	assert(t, p.AddTaxes(duty, levy, vat) == nil)
+	assertMoneyValue(t, p.Gross(), 6770)
+	assertMoneyValue(t, p.Net(), 5000)
+
+	bytes, _ := json.Marshal(p)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£67.70","net":"£50.00","tax":{"total":"£17.70","detail":[{"description":"Duty","label":"Duty 12%","base":"net","mode":"exclusive","rate":"12","amount":"£6.00"},{"description":"Levy","label":"Levy 1%","base":"net","mode":"exclusive","rate":"1","amount":"£0.50"},{"description":"Import VAT","label":"Import VAT 20%","base":"compound","mode":"exclusive","rate":"20","on":["Duty"],"amount":"£11.20"}]}}`)
+}
+
+func TestAddGrossTaxesMatchesAddTaxPercent(t *testing.T) {
+	vat, _ := GrossTax("VAT", "15")
+	small, _ := GrossTax("Small order", "5")
+
+	p1, _ := PriceFromSubunits("GBP", 2083, nil)
+	assert(t, p1.AddTaxes(vat, small) == nil)
+
+	p2, _ := PriceFromSubunits("GBP", 2083, nil)
+	p2.AddTaxPercent(15, "VAT")
+	p2.AddTaxPercent(5, "Small order")
+
+	assertMoneyValue(t, p1.Gross(), 2515)
+	assertMoneyValue(t, p2.Gross(), 2515)
+	assert(t, len(p2.Taxes()) == 2)
+	assert(t, p2.Taxes()[1].String() == "Small order 5% TaxOnGross")
+}
+
+func TestAddPerUnitTaxes(t *testing.T) {
+	deposit, _ := MoneyGBP(10)
+	vat, _ := GrossTax("VAT", "20")
+
+	p, _ := PriceFromSubunits("GBP", 100, nil)
+	assert(t, p.AddTaxes(UnitTax("Deposit", deposit), vat) == nil)
+	assertMoneyValue(t, p.Gross(), 132)
+	assertMoneyValue(t, p.Tax(), 32)
+
+	p = p.Mul(6)
+	assertMoneyValue(t, p.Gross(), 792)
+	assertMoneyValue(t, p.Net(), 600)
+}
+
+func TestIncludeTaxes(t *testing.T) {
+	gst, _ := NetTax("GST", "5")
+	qst, _ := CompoundTax("QST", "9.975", "GST")
+
+	p, _ := PriceFromSubunits("CAD", 11547, nil)
+	assert(t, p.IncludeTaxes(gst, qst) == nil)
+	assertMoneyValue(t, p.Gross(), 11547)
+	assertMoneyValue(t, p.Net(), 10000)
+	assertMoneyValue(t, p.Tax(), 1547)
+
+	bytes, _ := json.Marshal(p)
+	assertJSON(t, bytes, `{"currency":"CAD","gross":"$115.47","net":"$100.00","tax":{"total":"$15.47","detail":[{"description":"GST","label":"GST 5%","base":"net","mode":"inclusive","rate":"5","amount":"$5.00"},{"description":"QST","label":"QST 9.975%","base":"compound","mode":"inclusive","rate":"9.975","on":["GST"],"amount":"$10.47"}]}}`)
+
+	deposit, _ := MoneyGBP(10)
+	vat, _ := GrossTax("VAT", "20")
+
+	p, _ = PriceFromSubunits("GBP", 1210, nil)
+	assert(t, p.IncludeTaxes(UnitTax("Deposit", deposit), vat) == nil)
+	assertMoneyValue(t, p.Gross(), 1210)
+	assertMoneyValue(t, p.Net(), 998)
+	assertMoneyValue(t, p.Tax(), 212)
+}
+
+func TestIncludeTaxesRoundTrip(t *testing.T) {
+	duty, _ := NetTax("Duty", "12.5")
+	vat, _ := CompoundTax("VAT", "20", "Duty")
+
+	for net := int64(1); net < 2000; net += 7 {
+		p1, _ := PriceFromSubunits("GBP", net, nil)
+		assert(t, p1.AddTaxes(duty, vat) == nil)
+
+		p2, _ := PriceFromSubunits("GBP", p1.Gross().Value(), nil)
+		assert(t, p2.IncludeTaxes(duty, vat) == nil)
+
+		assertMoneyValue(t, p2.Net(), net)
+		assertMoneyValue(t, p2.Tax(), p1.Tax().Value())
+	}
+}
+
+func TestTaxBases(t *testing.T) {
+	gst, _ := NetTax("GST", "5")
+	qst, _ := CompoundTax("QST", "9.975", "GST")
+	hst, _ := GrossTax("HST", "1")
+	levy, _ := MoneyFromSubunits("CAD", 25, nil)
+
+	p, _ := PriceFromSubunits("CAD", 10000, nil)
+	assert(t, p.AddTaxes(gst, qst) == nil)
+	p.AddTax(levy, "Levy")
+	assert(t, p.AddTaxes(hst) == nil)
+
+	bases, err := p.TaxBases()
+	assert(t, err == nil)
+	assert(t, len(bases) == 4)
+	assertMoneyValue(t, bases[0], 10000)
+	assertMoneyValue(t, bases[1], 10500)
+	assertMoneyValue(t, bases[2], 0)
+	assertMoneyValue(t, bases[3], 11572)
+}
+
+func TestTaxesAreRecorded(t *testing.T) {
+	gst, _ := NetTax("GST", "5")
+	qst, _ := CompoundTax("QST", "9.975", "GST")
+	levy, _ := MoneyFromSubunits("CAD", 25, nil)
+
+	p, _ := PriceFromSubunits("CAD", 10000, nil)
+	assert(t, p.AddTaxes(gst, qst) == nil)
+	p.IncludeTax(levy, "Levy")
+
+	ts := p.Taxes()
+	assert(t, len(ts) == 3)
+	assert(t, ts[0].String() == "GST 5% TaxOnNet")
+	assert(t, ts[1].String() == "QST 9.975% TaxCompound on GST")
+	assert(t, ts[2].String() == "Levy $0.25 TaxPerUnit")
+
+	// The recorded taxes can't be modified through the returned slice.
+	ts[1].On[0] = "PST"
+	ts[1].Rate.SetInt64(0)
+	assert(t, p.Taxes()[1].String() == "QST 9.975% TaxCompound on GST")
+
+	parts := p.Split(2)
+	assert(t, len(parts[1].Taxes()) == 3)
+
+	other, _ := PriceFromSubunits("CAD", 100, nil)
+	other.AddTaxPercent(5, "GST")
+	other.AddTaxPercent(1, "Eco")
+	ts = p.Add(other).Taxes()
+	assert(t, len(ts) == 4)
+	assert(t, ts[3].String() == "Eco 1% TaxOnGross")
+}
+
+func TestTaxesErrors(t *testing.T) {
+	qst, _ := CompoundTax("QST", "9.975", "GST")
+	p, _ := PriceFromSubunits("CAD", 10000, nil)
+	assert(t, p.AddTaxes(qst) != nil)
+	assert(t, p.IncludeTaxes(qst) != nil)
+	assertMoneyValue(t, p.Gross(), 10000)
+	assert(t, len(p.Taxes()) == 0)
+
+	deposit, _ := MoneyGBP(10)
+	err := p.AddTaxes(UnitTax("Deposit", deposit))
+	assert(t, errors.Is(err, ErrCurrencyMismatch))
+
+	refund, _ := NetTax("Refund", "-100")
+	err = p.IncludeTaxes(refund)
+	assert(t, errors.Is(err, ErrDivideByZero))
+
+	assert(t, p.AddTaxes(Tax{Description: "VAT"}) != nil)
+
+	huge, _ := NetTax("Huge", "100000000000000000000")
+	assert(t, errors.Is(p.AddTaxes(huge), ErrOverflow))
+	assertMoneyValue(t, p.Gross(), 10000)
 }
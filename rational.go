@@ -0,0 +1,96 @@
+package mongo
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// MulRat is an exact arithmetic operator that multiplies the money object by
+// the fraction num/den. The result is calculated without any loss of
+// precision and then rounded once using the assigned rounding function.
+func (m Money) MulRat(num, den int64) (Money, error) {
+	if den == 0 {
+		return Money{}, fmt.Errorf("failed to multiply %s by %d/%d, %w", m, num, den, ErrDivideByZero)
+	}
+	return m.mulRat(big.NewRat(num, den))
+}
+
+// DivRat is an exact arithmetic operator that divides the money object by the
+// fraction num/den. The result is calculated without any loss of precision and
+// then rounded once using the assigned rounding function.
+func (m Money) DivRat(num, den int64) (Money, error) {
+	if num == 0 || den == 0 {
+		return Money{}, fmt.Errorf("failed to divide %s by %d/%d, %w", m, num, den, ErrDivideByZero)
+	}
+	return m.mulRat(big.NewRat(den, num))
+}
+
+// MulDecimal is an exact arithmetic operator that multiplies the money object
+// by a decimal number expressed as a string, e.g. "1.175". The result is
+// calculated without any loss of precision and then rounded once using the
+// assigned rounding function.
+func (m Money) MulDecimal(str string) (Money, error) {
+	r, err := parseDecimal(str)
+	if err != nil {
+		return Money{}, err
+	}
+	return m.mulRat(r)
+}
+
+// DivDecimal is an exact arithmetic operator that divides the money object by
+// a decimal number expressed as a string, e.g. "1.175". The result is
+// calculated without any loss of precision and then rounded once using the
+// assigned rounding function.
+func (m Money) DivDecimal(str string) (Money, error) {
+	r, err := parseDecimal(str)
+	if err != nil {
+		return Money{}, err
+	}
+	if r.Sign() == 0 {
+		return Money{}, fmt.Errorf("failed to divide %s by %s, %w", m, str, ErrDivideByZero)
+	}
+	return m.mulRat(r.Inv(r))
+}
+
+// mulRat multiplies the money object by a rational number and rounds the
+// result using the assigned rounding function.
+func (m Money) mulRat(r *big.Rat) (Money, error) {
+	v := new(big.Rat).SetInt64(m.value)
+	v.Mul(v, r)
+	value := roundRat(v, m.round)
+	if !value.IsInt64() {
+		return Money{}, fmt.Errorf("failed to multiply %s by %s, %w", m, r.RatString(), ErrOverflow)
+	}
+	m.value = value.Int64()
+	return m, nil
+}
+
+// decimalRegex matches a plain decimal number, e.g. "-17.5".
+var decimalRegex = regexp.MustCompile(`^[-+]?[0-9]+(\.[0-9]+)?$`)
+
+// parseDecimal parses a decimal number expressed as a string into an exact
+// rational number. Only plain decimal numbers are accepted, so fractions and
+// exponents such as "1/3" and "1e2" are rejected.
+func parseDecimal(str string) (*big.Rat, error) {
+	if !decimalRegex.MatchString(str) {
+		return nil, fmt.Errorf("failed to parse '%s' as a decimal number", str)
+	}
+	r, ok := new(big.Rat).SetString(str)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse '%s' as a decimal number", str)
+	}
+	return r, nil
+}
+
+// floatToRat converts a floating point number to the rational number of its
+// shortest decimal representation. For example, 17.5 becomes 35/2 and 0.1
+// becomes 1/10 rather than the binary approximation of 0.1.
+func floatToRat(f float64) (*big.Rat, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("failed to convert %g to a decimal number", f)
+	}
+	return parseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
@@ -0,0 +1,136 @@
+package mongo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoneyMulRat(t *testing.T) {
+	x, _ := MoneyGBP(1000)
+	m, err := x.MulRat(1, 3)
+	assert(t, err == nil)
+	assertMoneyValue(t, m, 333)
+
+	x, _ = MoneyFromSubunits("GBP", 1000, RoundUp)
+	m, _ = x.MulRat(1, 3)
+	assertMoneyValue(t, m, 334)
+
+	x, _ = MoneyFromSubunits("GBP", 9007199254740993, RoundHalfToEven)
+	m, _ = x.MulRat(3, 2)
+	assertMoneyValue(t, m, 13510798882111490)
+
+	_, err = x.MulRat(1, 0)
+	assert(t, errors.Is(err, ErrDivideByZero))
+
+	_, err = x.MulRat(10000, 1)
+	assert(t, errors.Is(err, ErrOverflow))
+}
+
+func TestMoneyDivRat(t *testing.T) {
+	x, _ := MoneyGBP(1000)
+	m, err := x.DivRat(3, 1)
+	assert(t, err == nil)
+	assertMoneyValue(t, m, 333)
+
+	m, _ = x.DivRat(-6, 1)
+	assertMoneyValue(t, m, -167)
+
+	_, err = x.DivRat(0, 1)
+	assert(t, errors.Is(err, ErrDivideByZero))
+}
+
+func TestMoneyMulDecimal(t *testing.T) {
+	x, _ := MoneyGBP(1000)
+	m, err := x.MulDecimal("1.175")
+	assert(t, err == nil)
+	assertMoneyValue(t, m, 1175)
+
+	x, _ = MoneyGBP(10)
+	m, _ = x.MulDecimal("0.15")
+	assertMoneyValue(t, m, 2)
+
+	x, _ = MoneyFromSubunits("GBP", 10, RoundHalfDown)
+	m, _ = x.MulDecimal("0.15")
+	assertMoneyValue(t, m, 1)
+
+	x, _ = MoneyFromSubunits("GBP", 4000000000000000001, RoundHalfUp)
+	m, _ = x.MulDecimal("0.5")
+	assertMoneyValue(t, m, 2000000000000000001)
+
+	_, err = x.MulDecimal("1,175")
+	assert(t, err != nil)
+
+	for _, str := range []string{"1/3", "50/3", "1e2", "", ".5", "1.", "0x10"} {
+		_, err = x.MulDecimal(str)
+		assert(t, err != nil)
+	}
+}
+
+func TestMoneyDivDecimal(t *testing.T) {
+	x, _ := MoneyGBP(1175)
+	m, err := x.DivDecimal("1.175")
+	assert(t, err == nil)
+	assertMoneyValue(t, m, 1000)
+
+	_, err = x.DivDecimal("0.000")
+	assert(t, errors.Is(err, ErrDivideByZero))
+
+	_, err = x.DivDecimal("one")
+	assert(t, err != nil)
+}
+
+func TestMoneyDivLarge(t *testing.T) {
+	x, _ := MoneyFromSubunits("GBP", 9007199254740993, RoundDown)
+	assertMoneyValue(t, x.Div(1), 9007199254740993)
+	assertMoneyValue(t, x.Div(0.1), 90071992547409930)
+}
+
+func TestPriceTaxDecimal(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 5500, nil)
+	err := p.AddTaxDecimal("17.5", "VAT")
+	assert(t, err == nil)
+	assertMoneyValue(t, p.Gross(), 6463)
+	assertMoneyValue(t, p.Net(), 5500)
+	assertMoneyValue(t, p.Tax(), 963)
+
+	p, _ = PriceFromSubunits("GBP", 6463, nil)
+	err = p.IncludeTaxDecimal("17.5", "VAT")
+	assert(t, err == nil)
+	assertMoneyValue(t, p.Gross(), 6463)
+	assertMoneyValue(t, p.Net(), 5500)
+	assertMoneyValue(t, p.Tax(), 963)
+
+	assert(t, p.AddTaxDecimal("17,5", "VAT") != nil)
+	assert(t, errors.Is(p.IncludeTaxDecimal("-100", "VAT"), ErrDivideByZero))
+}
+
+func TestPriceTaxPercentExact(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 10, nil)
+	p.AddTaxPercent(15, "VAT")
+	assertMoneyValue(t, p.Tax(), 2)
+
+	p, _ = PriceFromSubunits("GBP", 1000, nil)
+	p.AddTaxPercent(0.05, "Levy")
+	assertMoneyValue(t, p.Tax(), 1)
+
+	p, _ = PriceFromSubunits("GBP", 10000000000, nil)
+	p.AddTaxPercent(1e-7, "Levy")
+	assertMoneyValue(t, p.Tax(), 10)
+	assert(t, p.TaxLines()[0].Tax.Rate.RatString() == "1/10000000")
+}
+
+func TestPriceTaxPercentErrors(t *testing.T) {
+	p, _ := PriceFromSubunits("GBP", 1000, nil)
+	p.AddTaxPercent(math.NaN(), "VAT")
+	p.AddTaxPercent(math.Inf(1), "VAT")
+	p.IncludeTaxPercent(math.NaN(), "VAT")
+	p.IncludeTaxPercent(-100, "VAT")
+	assertMoneyValue(t, p.Gross(), 1000)
+	assert(t, len(p.TaxLines()) == 0)
+
+	p, _ = PriceFromSubunits("GBP", math.MaxInt64/2, nil)
+	p.AddTaxPercent(200, "VAT")
+	assertMoneyValue(t, p.Gross(), math.MaxInt64/2)
+	assert(t, len(p.TaxLines()) == 0)
+}
@@ -1,26 +1,78 @@
 package mongo
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
 
-// roundFunc is the type all the following rounding functions satisfy.
-type roundFunc func(float64) int64
+// RoundingMode is a function that rounds a number to an integer. The package
+// provides a function for each of the modes below, which are named by String
+// and encoded as text, e.g. "half-even". A nil rounding mode means
+// RoundHalfUp. Other functions can be used but can't be encoded.
+//
+//	Mode      Function            Text       2.5  1.5  0.6  -0.6  -1.5  -2.5
+//	Ceiling   RoundCeiling        ceiling      3    2    1     0    -1    -2
+//	Floor     RoundFloor          floor        2    1    0    -1    -2    -3
+//	Up        RoundAwayFromZero   up           3    2    1    -1    -2    -3
+//	Down      RoundTowardZero     down         2    1    0     0    -1    -2
+//	HalfUp    RoundHalfUp         half-up      3    2    1    -1    -2    -3
+//	HalfDown  RoundHalfDown       half-down    2    1    1    -1    -1    -2
+//	HalfEven  RoundHalfToEven     half-even    2    2    1    -1    -2    -2
+//	HalfOdd   RoundHalfToOdd      half-odd     3    1    1    -1    -1    -3
+//	05Up      Round05Up           05up         2    1    1    -1    -1    -2
+//
+// Up, Down and the half modes are symmetric, so a negative number rounds to
+// the negated result of its absolute value. RoundUp and RoundDown are the
+// same as Ceiling and Floor.
+type RoundingMode func(float64) int64
 
-// RoundUp is a standard rounding function that always rounds up.
+// RoundCeiling rounds towards positive infinity.
+func RoundCeiling(f float64) int64 {
+	return int64(math.Ceil(f))
+}
+
+// RoundFloor rounds towards negative infinity.
+func RoundFloor(f float64) int64 {
+	return int64(math.Floor(f))
+}
+
+// RoundAwayFromZero rounds away from zero, i.e. up in magnitude.
+func RoundAwayFromZero(f float64) int64 {
+	t := math.Trunc(f)
+	if t == f {
+		return int64(t)
+	}
+	return int64(t + math.Copysign(1, f))
+}
+
+// RoundTowardZero rounds towards zero, i.e. down in magnitude, which truncates
+// the fraction.
+func RoundTowardZero(f float64) int64 {
+	return int64(math.Trunc(f))
+}
+
+// RoundUp is a standard rounding function that always rounds up. It's the
+// same as RoundCeiling, so negative numbers are rounded towards zero.
 func RoundUp(f float64) int64 {
 	return int64(math.Ceil(f))
 }
 
-// RoundDown is a standard rounding function that always rounds down.
+// RoundDown is a standard rounding function that always rounds down. It's the
+// same as RoundFloor, so negative numbers are rounded away from zero.
 func RoundDown(f float64) int64 {
 	return int64(math.Floor(f))
 }
 
-// RoundHalfUp is a standard rounding function that rounds 0.5 and above up.
+// RoundHalfUp is a standard rounding function that rounds to the nearest
+// integer with halves rounded away from zero, so -2.5 rounds to -3.
 func RoundHalfUp(f float64) int64 {
 	return int64(math.Round(f))
 }
 
-// RoundHalfDown is a standard rounding function that rounds 0.5 and below down.
+// RoundHalfDown is a standard rounding function that rounds to the nearest
+// integer with halves rounded towards zero, so -2.5 rounds to -2.
 func RoundHalfDown(f float64) int64 {
 	t := math.Trunc(f)
 	if math.Abs(f-t) <= 0.5 {
@@ -34,3 +86,136 @@ func RoundHalfDown(f float64) int64 {
 func RoundHalfToEven(f float64) int64 {
 	return int64(math.RoundToEven(f))
 }
+
+// RoundHalfToOdd rounds to the nearest integer with halves rounded to the
+// nearest odd number.
+func RoundHalfToOdd(f float64) int64 {
+	t := math.Trunc(f)
+	d := math.Abs(f - t)
+	if d < 0.5 || (d == 0.5 && math.Mod(t, 2) != 0) {
+		return int64(t)
+	}
+	return int64(t + math.Copysign(1, f))
+}
+
+// Round05Up rounds towards zero, unless the last digit of the result would be
+// 0 or 5, in which case it rounds away from zero. This is useful for rounding
+// again later without double rounding errors.
+func Round05Up(f float64) int64 {
+	t := math.Trunc(f)
+	if t == f {
+		return int64(t)
+	}
+	if digit := math.Mod(math.Abs(t), 10); digit != 0 && digit != 5 {
+		return int64(t)
+	}
+	return int64(t + math.Copysign(1, f))
+}
+
+// roundingModes are the names of the package's rounding modes. RoundUp and
+// RoundDown are named as the modes they behave like.
+var roundingModes = []struct {
+	name string
+	text string
+	f    RoundingMode
+}{
+	{"Ceiling", "ceiling", RoundCeiling},
+	{"Floor", "floor", RoundFloor},
+	{"Up", "up", RoundAwayFromZero},
+	{"Down", "down", RoundTowardZero},
+	{"HalfUp", "half-up", RoundHalfUp},
+	{"HalfDown", "half-down", RoundHalfDown},
+	{"HalfEven", "half-even", RoundHalfToEven},
+	{"HalfOdd", "half-odd", RoundHalfToOdd},
+	{"05Up", "05up", Round05Up},
+	{"Ceiling", "ceiling", RoundUp},
+	{"Floor", "floor", RoundDown},
+}
+
+// modeIndex returns the index of the rounding mode in roundingModes or -1 if
+// it's not one of the package's modes. A nil mode is RoundHalfUp.
+func (r RoundingMode) modeIndex() int {
+	if r == nil {
+		r = RoundHalfUp
+	}
+	pc := reflect.ValueOf(r).Pointer()
+	for i, m := range roundingModes {
+		if reflect.ValueOf(m.f).Pointer() == pc {
+			return i
+		}
+	}
+	return -1
+}
+
+// String is an implementation of fmt.Stringer, e.g. "HalfEven". Functions
+// declared outside of this package return "Custom".
+func (r RoundingMode) String() string {
+	if i := r.modeIndex(); i >= 0 {
+		return roundingModes[i].name
+	}
+	return "Custom"
+}
+
+// MarshalText is an implementation of encoding.TextMarshaler, e.g.
+// "half-even". Functions declared outside of this package can't be encoded.
+func (r RoundingMode) MarshalText() ([]byte, error) {
+	i := r.modeIndex()
+	if i < 0 {
+		return nil, fmt.Errorf("failed to marshal rounding mode, %s is not a rounding mode of this package", roundName(r))
+	}
+	return []byte(roundingModes[i].text), nil
+}
+
+// UnmarshalText is an implementation of encoding.TextUnmarshaler.
+func (r *RoundingMode) UnmarshalText(text []byte) error {
+	for _, m := range roundingModes {
+		if m.text == string(text) {
+			*r = m.f
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to unmarshal rounding mode '%s'", text)
+}
+
+// jsonField returns the rounding mode as a JSON object field to be appended
+// to the fields of money. It's empty for RoundHalfUp, which is the default
+// when unmarshalling, and for functions that can't be encoded.
+func (r RoundingMode) jsonField() string {
+	i := r.modeIndex()
+	if i < 0 || roundingModes[i].text == "half-up" {
+		return ""
+	}
+	return fmt.Sprintf(`, "rounding": "%s"`, roundingModes[i].text)
+}
+
+// roundRat rounds a rational number to an integer using the passed rounding
+// function. The rounding function is only passed a small number with the same
+// sign, the same last integer digit and a fractional part that's less than,
+// equal to or greater than a half in the same way as the rational number. This
+// makes the result exact no matter how large the rational number is. A nil
+// rounding mode is RoundHalfUp.
+func roundRat(r *big.Rat, f RoundingMode) *big.Int {
+	if f == nil {
+		f = RoundHalfUp
+	}
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+
+	frac := 0.25
+	switch new(big.Int).Abs(rem.Lsh(rem, 1)).Cmp(r.Denom()) {
+	case 0:
+		frac = 0.5
+	case 1:
+		frac = 0.75
+	}
+	if r.Sign() < 0 {
+		frac = -frac
+	}
+
+	digit := new(big.Int).Rem(q, big.NewInt(10)).Int64()
+	adjust := f(float64(digit)+frac) - digit
+
+	return q.Add(q, big.NewInt(adjust))
+}
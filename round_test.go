@@ -1,6 +1,8 @@
 package mongo
 
 import (
+	"encoding/json"
+	"math/big"
 	"testing"
 )
 
@@ -52,3 +54,187 @@ func TestRoundHalfToEven(t *testing.T) {
 	assertValue(t, RoundHalfToEven(-10.5), -10)
 	assertValue(t, RoundHalfToEven(-10.25), -10)
 }
+
+func TestRoundRat(t *testing.T) {
+	tests := []struct {
+		num, den int64
+		f        RoundingMode
+		expected int64
+	}{
+		{21, 2, RoundUp, 11},
+		{-21, 2, RoundUp, -10},
+		{21, 2, RoundDown, 10},
+		{-21, 2, RoundDown, -11},
+		{21, 2, RoundHalfUp, 11},
+		{-21, 2, RoundHalfUp, -11},
+		{21, 2, RoundHalfDown, 10},
+		{-21, 2, RoundHalfDown, -10},
+		{21, 2, RoundHalfToEven, 10},
+		{23, 2, RoundHalfToEven, 12},
+		{-23, 2, RoundHalfToEven, -12},
+		{41, 4, RoundHalfUp, 10},
+		{43, 4, RoundHalfDown, 11},
+		{-1, 4, RoundDown, -1},
+		{-1, 4, RoundUp, 0},
+		{20, 2, RoundUp, 10},
+	}
+	for _, test := range tests {
+		r := roundRat(big.NewRat(test.num, test.den), test.f)
+		assertValue(t, r.Int64(), test.expected)
+	}
+
+	// A half that's too small to be represented by a float64.
+	n, _ := new(big.Int).SetString("90071992547409935", 10)
+	r := roundRat(new(big.Rat).SetFrac(n, big.NewInt(10)), RoundHalfToEven)
+	assert(t, r.String() == "9007199254740994")
+}
+
+func TestRoundingModes(t *testing.T) {
+	inputs := []float64{2.5, 1.5, 0.6, -0.6, -1.5, -2.5}
+	tests := []struct {
+		f        RoundingMode
+		expected []int64
+	}{
+		{RoundCeiling, []int64{3, 2, 1, 0, -1, -2}},
+		{RoundFloor, []int64{2, 1, 0, -1, -2, -3}},
+		{RoundAwayFromZero, []int64{3, 2, 1, -1, -2, -3}},
+		{RoundTowardZero, []int64{2, 1, 0, 0, -1, -2}},
+		{RoundHalfUp, []int64{3, 2, 1, -1, -2, -3}},
+		{RoundHalfDown, []int64{2, 1, 1, -1, -1, -2}},
+		{RoundHalfToEven, []int64{2, 2, 1, -1, -2, -2}},
+		{RoundHalfToOdd, []int64{3, 1, 1, -1, -1, -3}},
+		{Round05Up, []int64{2, 1, 1, -1, -1, -2}},
+	}
+	for _, test := range tests {
+		for i, f := range inputs {
+			if v := test.f(f); v != test.expected[i] {
+				t.Errorf("Failed asserting %s(%g) = %d, returned %d\n", test.f, f, test.expected[i], v)
+			}
+		}
+	}
+
+	assertValue(t, RoundAwayFromZero(3), 3)
+	assertValue(t, RoundHalfToOdd(3.25), 3)
+	assertValue(t, RoundHalfToOdd(-3.75), -4)
+	assertValue(t, Round05Up(15.2), 16)
+	assertValue(t, Round05Up(-15.2), -16)
+	assertValue(t, Round05Up(14.9), 14)
+	assertValue(t, Round05Up(-10), -10)
+}
+
+func TestRoundRatModes(t *testing.T) {
+	tests := []struct {
+		num, den int64
+		f        RoundingMode
+		expected int64
+	}{
+		{21, 2, RoundAwayFromZero, 11},
+		{-21, 2, RoundAwayFromZero, -11},
+		{-1, 4, RoundAwayFromZero, -1},
+		{21, 2, RoundTowardZero, 10},
+		{-21, 2, RoundTowardZero, -10},
+		{21, 2, RoundHalfToOdd, 11},
+		{23, 2, RoundHalfToOdd, 11},
+		{-23, 2, RoundHalfToOdd, -11},
+		{-25, 2, RoundHalfToOdd, -13},
+		{101, 10, Round05Up, 11},
+		{-101, 10, Round05Up, -11},
+		{151, 10, Round05Up, 16},
+		{161, 10, Round05Up, 16},
+		{-1, 4, Round05Up, -1},
+		{-1, 4, RoundCeiling, 0},
+		{-1, 4, RoundFloor, -1},
+	}
+	for _, test := range tests {
+		r := roundRat(big.NewRat(test.num, test.den), test.f)
+		assertValue(t, r.Int64(), test.expected)
+	}
+
+	// The last digit decides, however large the number.
+	n, _ := new(big.Int).SetString("900719925474099351", 10)
+	r := roundRat(new(big.Rat).SetFrac(n, big.NewInt(100)), Round05Up)
+	assert(t, r.String() == "9007199254740993")
+	n, _ = new(big.Int).SetString("900719925474099501", 10)
+	r = roundRat(new(big.Rat).SetFrac(n, big.NewInt(100)), Round05Up)
+	assert(t, r.String() == "9007199254740996")
+}
+
+func TestRoundingModeText(t *testing.T) {
+	tests := []struct {
+		f    RoundingMode
+		name string
+		text string
+	}{
+		{RoundCeiling, "Ceiling", "ceiling"},
+		{RoundFloor, "Floor", "floor"},
+		{RoundAwayFromZero, "Up", "up"},
+		{RoundTowardZero, "Down", "down"},
+		{RoundHalfUp, "HalfUp", "half-up"},
+		{RoundHalfDown, "HalfDown", "half-down"},
+		{RoundHalfToEven, "HalfEven", "half-even"},
+		{RoundHalfToOdd, "HalfOdd", "half-odd"},
+		{Round05Up, "05Up", "05up"},
+		{RoundUp, "Ceiling", "ceiling"},
+		{RoundDown, "Floor", "floor"},
+		{nil, "HalfUp", "half-up"},
+	}
+	for _, test := range tests {
+		assert(t, test.f.String() == test.name)
+		b, err := test.f.MarshalText()
+		assert(t, err == nil)
+		assert(t, string(b) == test.text)
+
+		var r RoundingMode
+		assert(t, r.UnmarshalText(b) == nil)
+		assert(t, r.String() == test.name)
+		assertValue(t, r(-2.5), roundRat(big.NewRat(-5, 2), test.f).Int64())
+	}
+
+	custom := RoundingMode(func(f float64) int64 { return 0 })
+	assert(t, custom.String() == "Custom")
+	_, err := custom.MarshalText()
+	assert(t, err != nil)
+
+	var r RoundingMode
+	assert(t, r.UnmarshalText([]byte("sideways")) != nil)
+}
+
+func TestRoundingModeJson(t *testing.T) {
+	for _, f := range []RoundingMode{RoundCeiling, RoundFloor, RoundAwayFromZero, RoundTowardZero, RoundHalfUp, RoundHalfDown, RoundHalfToEven, RoundHalfToOdd, Round05Up} {
+		m, _ := MoneyFromSubunits("GBP", 1055, f)
+		b, err := json.Marshal(m)
+		assert(t, err == nil)
+
+		var result Money
+		assert(t, json.Unmarshal(b, &result) == nil)
+		assert(t, result.Rounding().String() == f.String())
+		assertValue(t, result.Div(-4).Value(), m.Div(-4).Value())
+
+		p, _ := PriceFromSubunits("GBP", 1055, f)
+		b, err = json.Marshal(p)
+		assert(t, err == nil)
+
+		var price Price
+		assert(t, json.Unmarshal(b, &price) == nil)
+		assert(t, price.Gross().Rounding().String() == f.String())
+
+		big, _ := BigMoneyFromSubunits("GBP", big.NewInt(1055), f)
+		b, err = json.Marshal(big)
+		assert(t, err == nil)
+
+		var bigResult BigMoney
+		assert(t, json.Unmarshal(b, &bigResult) == nil)
+		assert(t, bigResult.Rounding().String() == f.String())
+	}
+
+	m, _ := MoneyFromSubunits("GBP", 1055, RoundHalfToOdd)
+	b, _ := json.Marshal(m)
+	assertJSON(t, b, `{"currency":"GBP","amount":"£10.55","rounding":"half-odd"}`)
+
+	m, _ = MoneyFromSubunits("GBP", 1055, nil)
+	b, _ = json.Marshal(m)
+	assertJSON(t, b, `{"currency":"GBP","amount":"£10.55"}`)
+
+	var result Money
+	assert(t, json.Unmarshal([]byte(`{"currency":"GBP","amount":"£10.55","rounding":"sideways"}`), &result) != nil)
+}
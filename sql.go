@@ -0,0 +1,207 @@
+package mongo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SQLEncoding specifies how a money object is stored in a database column.
+type SQLEncoding int
+
+const (
+	// SQLComposite stores the currency code and subunits as text, e.g. "GBP 1055".
+	SQLComposite SQLEncoding = iota
+
+	// SQLSubunits stores only the subunits as an integer. The currency is
+	// fixed by the column and not stored.
+	SQLSubunits
+
+	// SQLJSON stores the JSON representation of the money object.
+	SQLJSON
+)
+
+// SQLMoney adapts a money object for use with the database/sql package. It
+// implements sql.Scanner and driver.Valuer which money can't implement itself
+// because its Value method returns the subunits.
+type SQLMoney struct {
+	Money    *Money      // The money object to read from or scan into.
+	Encoding SQLEncoding // The column encoding.
+	Currency string      // The ISO 4217 currency code of an SQLSubunits column.
+}
+
+// SQLMoneyComposite returns an adapter that stores money as text containing
+// the currency code and subunits, e.g. "GBP 1055".
+func SQLMoneyComposite(m *Money) SQLMoney {
+	return SQLMoney{Money: m, Encoding: SQLComposite}
+}
+
+// SQLMoneySubunits returns an adapter that stores money as an integer of
+// subunits in a column that only ever holds the passed currency.
+func SQLMoneySubunits(m *Money, currIsoCode string) SQLMoney {
+	return SQLMoney{Money: m, Encoding: SQLSubunits, Currency: currIsoCode}
+}
+
+// SQLMoneyJSON returns an adapter that stores money as JSON.
+func SQLMoneyJSON(m *Money) SQLMoney {
+	return SQLMoney{Money: m, Encoding: SQLJSON}
+}
+
+// Value is an implementation of driver.Valuer.
+func (s SQLMoney) Value() (driver.Value, error) {
+	if s.Money == nil {
+		return nil, nil
+	}
+
+	m := *s.Money
+
+	switch s.Encoding {
+	case SQLComposite:
+		return fmt.Sprintf("%s %d", m.IsoCode(), m.value), nil
+
+	case SQLSubunits:
+		if m.IsoCode() != s.Currency {
+			return nil, fmt.Errorf("failed to store money, currency '%s' does not match column currency '%s'", m.IsoCode(), s.Currency)
+		}
+		return m.value, nil
+
+	case SQLJSON:
+		b, err := json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+
+	return nil, fmt.Errorf("failed to store money, unknown encoding %d", s.Encoding)
+}
+
+// Scan is an implementation of sql.Scanner. For the composite and subunits
+// encodings the rounding function of the money object being scanned into is
+// kept if it has one, otherwise RoundHalfUp is used. The JSON encoding uses
+// the encoded rounding mode, which is RoundHalfUp if none is present.
+func (s SQLMoney) Scan(src any) error {
+	if s.Money == nil {
+		return fmt.Errorf("failed to scan money, no destination")
+	}
+	if src == nil {
+		return fmt.Errorf("failed to scan money, value is NULL")
+	}
+
+	switch s.Encoding {
+	case SQLComposite:
+		str, ok := sqlString(src)
+		if !ok {
+			return fmt.Errorf("failed to scan money, unsupported type %T", src)
+		}
+		code, value, ok := strings.Cut(strings.TrimSpace(str), " ")
+		if !ok {
+			return fmt.Errorf("failed to scan money, '%s' is not in the format 'CODE SUBUNITS'", str)
+		}
+		subunits, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to scan money, %w", err)
+		}
+		m, err := MoneyFromSubunits(code, subunits, s.Money.round)
+		if err != nil {
+			return fmt.Errorf("failed to scan money, %w", err)
+		}
+		*s.Money = m
+		return nil
+
+	case SQLSubunits:
+		var subunits int64
+		switch v := src.(type) {
+		case int64:
+			subunits = v
+		default:
+			str, ok := sqlString(src)
+			if !ok {
+				return fmt.Errorf("failed to scan money, unsupported type %T", src)
+			}
+			var err error
+			subunits, err = strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+			if err != nil {
+				return fmt.Errorf("failed to scan money, %w", err)
+			}
+		}
+		m, err := MoneyFromSubunits(s.Currency, subunits, s.Money.round)
+		if err != nil {
+			return fmt.Errorf("failed to scan money, %w", err)
+		}
+		*s.Money = m
+		return nil
+
+	case SQLJSON:
+		str, ok := sqlString(src)
+		if !ok {
+			return fmt.Errorf("failed to scan money, unsupported type %T", src)
+		}
+		var m Money
+		if err := json.Unmarshal([]byte(str), &m); err != nil {
+			return fmt.Errorf("failed to scan money, %w", err)
+		}
+		*s.Money = m
+		return nil
+	}
+
+	return fmt.Errorf("failed to scan money, unknown encoding %d", s.Encoding)
+}
+
+// SQLPrice adapts a price object for use with the database/sql package. It
+// implements sql.Scanner and driver.Valuer and stores the price as JSON,
+// including the tax detail.
+type SQLPrice struct {
+	Price *Price // The price object to read from or scan into.
+}
+
+// SQLPriceJSON returns an adapter that stores a price as JSON.
+func SQLPriceJSON(p *Price) SQLPrice {
+	return SQLPrice{Price: p}
+}
+
+// Value is an implementation of driver.Valuer.
+func (s SQLPrice) Value() (driver.Value, error) {
+	if s.Price == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(*s.Price)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan is an implementation of sql.Scanner. The price uses the encoded
+// rounding mode, which is RoundHalfUp if none is present.
+func (s SQLPrice) Scan(src any) error {
+	if s.Price == nil {
+		return fmt.Errorf("failed to scan price, no destination")
+	}
+	if src == nil {
+		return fmt.Errorf("failed to scan price, value is NULL")
+	}
+	str, ok := sqlString(src)
+	if !ok {
+		return fmt.Errorf("failed to scan price, unsupported type %T", src)
+	}
+	var p Price
+	if err := json.Unmarshal([]byte(str), &p); err != nil {
+		return fmt.Errorf("failed to scan price, %w", err)
+	}
+	*s.Price = p
+	return nil
+}
+
+// sqlString returns the textual content of a value passed to a scanner.
+func sqlString(src any) (string, bool) {
+	switch v := src.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
@@ -0,0 +1,221 @@
+package mongo
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is an in-memory database driver holding a single table with a
+// single column. It understands the queries INSERT, SELECT and DELETE.
+type fakeDriver struct {
+	mu   sync.Mutex
+	rows []driver.Value
+}
+
+type fakeConn struct{ d *fakeDriver }
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+type fakeRows struct {
+	rows []driver.Value
+	pos  int
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	switch s.query {
+	case "INSERT":
+		s.c.d.rows = append(s.c.d.rows, args[0])
+	case "DELETE":
+		s.c.d.rows = nil
+	default:
+		return nil, errors.New("unknown query")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	if s.query != "SELECT" {
+		return nil, errors.New("unknown query")
+	}
+	return &fakeRows{rows: append([]driver.Value{}, s.c.d.rows...)}, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"value"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	dest[0] = r.rows[r.pos]
+	r.pos++
+	return nil
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("mongo-fake", fake)
+}
+
+func openFakeDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("mongo-fake", "")
+	if err != nil {
+		t.Fatalf("Failed to open fake database: %s", err)
+	}
+	if _, err := db.Exec("DELETE"); err != nil {
+		t.Fatalf("Failed to clear fake database: %s", err)
+	}
+	return db
+}
+
+func TestSQLMoneyComposite(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	m, _ := MoneyFromSubunits("CLF", 1578964418, nil)
+	if _, err := db.Exec("INSERT", SQLMoneyComposite(&m)); err != nil {
+		t.Fatalf("Failed to insert money: %s", err)
+	}
+
+	assert(t, fake.rows[0] == "CLF 1578964418")
+
+	r, _ := MoneyFromSubunits("GBP", 0, RoundUp)
+	if err := db.QueryRow("SELECT").Scan(SQLMoneyComposite(&r)); err != nil {
+		t.Fatalf("Failed to scan money: %s", err)
+	}
+
+	assertMoneyString(t, r, "CLF", "UF157.896,4418")
+	assertValue(t, r.Div(3).Value(), 526321473)
+}
+
+func TestSQLMoneySubunits(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	m, _ := MoneyGBP(1055)
+	if _, err := db.Exec("INSERT", SQLMoneySubunits(&m, "GBP")); err != nil {
+		t.Fatalf("Failed to insert money: %s", err)
+	}
+
+	assert(t, fake.rows[0] == int64(1055))
+
+	var r Money
+	if err := db.QueryRow("SELECT").Scan(SQLMoneySubunits(&r, "GBP")); err != nil {
+		t.Fatalf("Failed to scan money: %s", err)
+	}
+
+	assertMoneyString(t, r, "GBP", "£10.55")
+
+	e, _ := MoneyEUR(1055)
+	if _, err := db.Exec("INSERT", SQLMoneySubunits(&e, "GBP")); err == nil {
+		t.Errorf("SQLMoneySubunits failed to error on a currency mismatch")
+	}
+}
+
+func TestSQLMoneyJSON(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	m, _ := MoneyFromSubunits("JOD", 2462486, nil)
+	if _, err := db.Exec("INSERT", SQLMoneyJSON(&m)); err != nil {
+		t.Fatalf("Failed to insert money: %s", err)
+	}
+
+	var r Money
+	if err := db.QueryRow("SELECT").Scan(SQLMoneyJSON(&r)); err != nil {
+		t.Fatalf("Failed to scan money: %s", err)
+	}
+
+	assertMoneyString(t, r, "JOD", "2,462.486 د.أ")
+
+	// No rounding mode is encoded for RoundHalfUp, so it isn't replaced by
+	// the destination's mode.
+	d, _ := MoneyFromSubunits("GBP", 0, RoundDown)
+	if err := SQLMoneyJSON(&d).Scan(`{"currency":"GBP","amount":"£10.00"}`); err != nil {
+		t.Fatalf("Failed to scan money: %s", err)
+	}
+	assert(t, d.Rounding().String() == "HalfUp")
+	assertValue(t, d.Div(6).Value(), 167)
+
+	d, _ = MoneyFromSubunits("GBP", 0, RoundHalfUp)
+	if err := SQLMoneyJSON(&d).Scan(`{"currency":"GBP","amount":"£10.00","rounding":"floor"}`); err != nil {
+		t.Fatalf("Failed to scan money: %s", err)
+	}
+	assertValue(t, d.Div(6).Value(), 166)
+}
+
+func TestSQLMoneyScanErrors(t *testing.T) {
+	var m Money
+	for _, s := range []SQLMoney{SQLMoneyComposite(&m), SQLMoneySubunits(&m, "GBP"), SQLMoneyJSON(&m)} {
+		if err := s.Scan(nil); err == nil {
+			t.Errorf("SQLMoney failed to error on NULL")
+		}
+		if err := s.Scan(1.5); err == nil {
+			t.Errorf("SQLMoney failed to error on a float")
+		}
+	}
+
+	for _, src := range []any{"GBP", "XXX 1055", "GBP 10.55", []byte("1055 GBP")} {
+		if err := SQLMoneyComposite(&m).Scan(src); err == nil {
+			t.Errorf("SQLMoneyComposite failed to error on %v", src)
+		}
+	}
+
+	if err := SQLMoneySubunits(&m, "XXX").Scan(int64(1055)); err == nil {
+		t.Errorf("SQLMoneySubunits failed to error on an unknown currency")
+	}
+}
+
+func TestSQLPrice(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	p, _ := PriceFromSubunits("GBP", 2083, nil)
+	p.AddTaxPercent(15, "VAT")
+	p.AddTaxPercent(5, "Small order")
+
+	if _, err := db.Exec("INSERT", SQLPriceJSON(&p)); err != nil {
+		t.Fatalf("Failed to insert price: %s", err)
+	}
+
+	var r Price
+	if err := db.QueryRow("SELECT").Scan(SQLPriceJSON(&r)); err != nil {
+		t.Fatalf("Failed to scan price: %s", err)
+	}
+
+	assertMoneyValue(t, r.Gross(), 2515)
+	assertMoneyValue(t, r.Net(), 2083)
+	assertMoneyValue(t, r.Tax(), 432)
+	assertMoneyValue(t, taxAmount(r.taxes.detail, "VAT"), 312)
+	assertMoneyValue(t, taxAmount(r.taxes.detail, "Small order"), 120)
+
+	d, _ := PriceFromSubunits("GBP", 0, RoundDown)
+	if err := SQLPriceJSON(&d).Scan(`{"currency":"GBP","gross":"£10.00"}`); err != nil {
+		t.Fatalf("Failed to scan price: %s", err)
+	}
+	assert(t, d.Gross().Rounding().String() == "HalfUp")
+
+	if err := SQLPriceJSON(&r).Scan(nil); err == nil {
+		t.Errorf("SQLPrice failed to error on NULL")
+	}
+}
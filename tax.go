@@ -3,57 +3,130 @@ package mongo
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"regexp"
 	"strings"
 
 	"golang.org/x/exp/slices"
 )
 
-type detail map[string]Money
+// TaxLine is the amount of a tax applied to a price.
+type TaxLine struct {
+	Tax    Tax   // The definition of the tax.
+	Amount Money // The amount of tax.
+}
 
-// Adds a new tax to the detail collection.
-// If the tax description exists the money value will be added to it.
-// This method returns a new map to make sure this operation is immutable
-// across price types.
-func (d detail) add(desc string, m Money) detail {
-	result := make(map[string]Money, 0)
+// detail is the breakdown of taxes in the order they were applied.
+type detail []TaxLine
 
-	for k, v := range d {
-		result[k] = v
-	}
+// Adds a new tax to the detail collection.
+// If the same tax has already been applied the money value will be added to
+// it. This method returns a new slice to make sure this operation is
+// immutable across price types.
+func (d detail) add(t Tax, m Money) detail {
+	result := make(detail, len(d), len(d)+1)
+	copy(result, d)
 
-	if _, ok := result[desc]; ok {
-		result[desc] = result[desc].Add(m)
+	if i := d.index(t); i >= 0 {
+		result[i].Amount = result[i].Amount.Add(m)
 	} else {
-		result[desc] = m
+		result = append(result, TaxLine{Tax: t.clone(), Amount: m})
 	}
 
 	return result
 }
 
+// Prepend adds the tax to the front of the tax details collection, or to the
+// line holding the same tax if there is one.
+func (d detail) prepend(t Tax, m Money) detail {
+	if i := d.index(t); i >= 0 {
+		return d.add(t, m)
+	}
+	result := make(detail, 0, len(d)+1)
+	result = append(result, TaxLine{Tax: t.clone(), Amount: m})
+	return append(result, d...)
+}
+
+// Index returns the index of the line holding the same tax or -1 if there
+// isn't one.
+func (d detail) index(t Tax) int {
+	key := t.key()
+	return slices.IndexFunc(d, func(l TaxLine) bool { return l.Tax.key() == key })
+}
+
 // Mul multiplies all the values in the tax details collection by the passed
 // amount.
 func (d detail) mul(n int64) detail {
-	result := make(map[string]Money, 0)
+	result := make(detail, len(d))
 
-	for k, v := range d {
-		result[k] = v.Mul(n)
+	for i, l := range d {
+		result[i] = TaxLine{Tax: l.Tax, Amount: l.Amount.Mul(n)}
 	}
 
 	return result
 }
 
-// MarshalJSON is an implementation of json.Marshaller.
+// Neg negates all the values in the tax details collection.
+func (d detail) neg() detail {
+	result := make(detail, len(d))
+
+	for i, l := range d {
+		result[i] = TaxLine{Tax: l.Tax, Amount: l.Amount.FlipSign()}
+	}
+
+	return result
+}
+
+// MarshalJSON is an implementation of json.Marshaller. Taxes are encoded in
+// the order they were applied.
 func (d detail) MarshalJSON() ([]byte, error) {
-	json := make([]string, 0)
+	lines := make([]taxJSON, 0, len(d))
+
+	for _, l := range d {
+		v := l.Tax.toJSON()
+		v.Amount = json.RawMessage(fmt.Sprintf("%q", l.Amount.String()))
+		lines = append(lines, v)
+	}
+
+	return json.Marshal(lines)
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. Because the detail
+// collection doesn't record a currency, each amount must be a full money
+// object, e.g. {"currency":"GBP","amount":"£1.57"}. Bare amount strings are
+// accepted when the detail is unmarshalled as part of a taxes object.
+func (d *detail) UnmarshalJSON(b []byte) error {
+	return d.unmarshal(b, Money{})
+}
 
-	for k, v := range d {
-		json = append(json, fmt.Sprintf(`{"amount": "%s", "description": "%s"}`, v.String(), k))
+// Unmarshal parses a JSON array of taxes into the detail collection. Bare
+// amount strings are parsed using the currency of the passed money object.
+func (d *detail) unmarshal(b []byte, currency Money) error {
+	var entries []taxJSON
+
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return fmt.Errorf("failed to unmarshal tax detail, %w", err)
 	}
 
-	// Because the json map's order is non-deterministic, sort for deterministic output.
-	slices.Sort(json)
+	result := make(detail, 0, len(entries))
+
+	for _, e := range entries {
+		m := currency.Clone(0)
+		if err := json.Unmarshal(e.Amount, &m); err != nil {
+			return fmt.Errorf("failed to unmarshal tax '%s', %w", e.Description, err)
+		}
+		if currency.currency.Code != "" && m.currency.Code != currency.currency.Code {
+			return fmt.Errorf("failed to unmarshal tax '%s', currency '%s' does not match '%s'", e.Description, m.currency.Code, currency.currency.Code)
+		}
+		t, err := e.tax(m)
+		if err != nil {
+			return err
+		}
+		result = result.add(t, m)
+	}
 
-	return []byte("[" + strings.Join(json, ",") + "]"), nil
+	*d = result
+	return nil
 }
 
 // Taxes is a structure that holds the taxes information of a price.
@@ -63,12 +136,32 @@ type taxes struct {
 }
 
 // Add adds a new tax to the taxes collection.
-func (t taxes) add(desc string, m Money) taxes {
-	t.detail = t.detail.add(desc, m)
+func (t taxes) add(tax Tax, m Money) taxes {
+	t.detail = t.detail.add(tax, m)
+	t.total = t.total.Add(m)
+	return t
+}
+
+// Prepend adds a tax to the front of the taxes collection.
+func (t taxes) prepend(tax Tax, m Money) taxes {
+	t.detail = t.detail.prepend(tax, m)
 	t.total = t.total.Add(m)
 	return t
 }
 
+// AddE is a checked version of add.
+func (t taxes) addE(tax Tax, m Money) (taxes, error) {
+	if i := t.detail.index(tax); i >= 0 {
+		if _, err := t.detail[i].Amount.AddE(m); err != nil {
+			return taxes{}, err
+		}
+	}
+	if _, err := t.total.AddE(m); err != nil {
+		return taxes{}, err
+	}
+	return t.add(tax, m), nil
+}
+
 // Mul multiplies taxes in the taxes collection.
 func (t taxes) mul(n int64) taxes {
 	t.detail = t.detail.mul(n)
@@ -76,6 +169,13 @@ func (t taxes) mul(n int64) taxes {
 	return t
 }
 
+// Neg negates taxes in the taxes collection.
+func (t taxes) neg() taxes {
+	t.detail = t.detail.neg()
+	t.total = t.total.FlipSign()
+	return t
+}
+
 // MarshalJSON is an implementation of json.Marshaller.
 func (t taxes) MarshalJSON() ([]byte, error) {
 	detail, err := json.Marshal(t.detail)
@@ -91,3 +191,414 @@ func (t taxes) MarshalJSON() ([]byte, error) {
 
 	return []byte(json), nil
 }
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. The taxes object
+// must already contain a total in the expected currency because the JSON
+// amounts don't record one.
+func (t *taxes) UnmarshalJSON(b []byte) error {
+	if t.total.currency.Code == "" {
+		return fmt.Errorf("failed to unmarshal taxes, no currency specified")
+	}
+
+	var v struct {
+		Total  json.RawMessage `json:"total"`
+		Detail json.RawMessage `json:"detail"`
+	}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal taxes, %w", err)
+	}
+
+	result := taxes{
+		total:  t.total.Clone(0),
+		detail: make(detail, 0),
+	}
+
+	if len(v.Detail) > 0 {
+		if err := result.detail.unmarshal(v.Detail, result.total); err != nil {
+			return err
+		}
+		for _, l := range result.detail {
+			result.total = result.total.Add(l.Amount)
+		}
+	}
+
+	if len(v.Total) > 0 {
+		total := t.total.Clone(0)
+		if err := json.Unmarshal(v.Total, &total); err != nil {
+			return fmt.Errorf("failed to unmarshal tax total, %w", err)
+		}
+		if total.currency.Code != result.total.currency.Code || total.value != result.total.value {
+			return fmt.Errorf("failed to unmarshal taxes, total %s does not equal the sum of the detail %s", total, result.total)
+		}
+	}
+
+	*t = result
+	return nil
+}
+
+// TaxBase specifies the amount a tax is calculated on.
+type TaxBase int
+
+const (
+	// TaxOnNet taxes are a percentage of the net only, so they're calculated
+	// in parallel with other taxes.
+	TaxOnNet TaxBase = iota
+
+	// TaxOnGross taxes are a percentage of the net plus every earlier tax.
+	TaxOnGross
+
+	// TaxCompound taxes are a percentage of the net plus the earlier taxes
+	// named in the tax's On field, e.g. import VAT charged on duty.
+	TaxCompound
+
+	// TaxPerUnit taxes are a fixed amount per unit, e.g. a bottle deposit.
+	TaxPerUnit
+)
+
+// taxBaseNames are the names of the tax bases used for text encoding.
+var taxBaseNames = []string{"net", "gross", "compound", "unit"}
+
+// String is an implementation of fmt.Stringer.
+func (b TaxBase) String() string {
+	switch b {
+	case TaxOnNet:
+		return "TaxOnNet"
+	case TaxOnGross:
+		return "TaxOnGross"
+	case TaxCompound:
+		return "TaxCompound"
+	case TaxPerUnit:
+		return "TaxPerUnit"
+	}
+	return fmt.Sprintf("TaxBase(%d)", int(b))
+}
+
+// MarshalText is an implementation of encoding.TextMarshaler, e.g. "net".
+func (b TaxBase) MarshalText() ([]byte, error) {
+	if b < 0 || int(b) >= len(taxBaseNames) {
+		return nil, fmt.Errorf("failed to marshal tax base %d", int(b))
+	}
+	return []byte(taxBaseNames[b]), nil
+}
+
+// UnmarshalText is an implementation of encoding.TextUnmarshaler.
+func (b *TaxBase) UnmarshalText(text []byte) error {
+	i := slices.Index(taxBaseNames, string(text))
+	if i < 0 {
+		return fmt.Errorf("failed to unmarshal tax base '%s'", text)
+	}
+	*b = TaxBase(i)
+	return nil
+}
+
+// TaxMode specifies whether a tax is added to the gross price or already
+// included in it.
+type TaxMode int
+
+const (
+	// TaxExclusive taxes are added to the gross price.
+	TaxExclusive TaxMode = iota
+
+	// TaxInclusive taxes are already included in the gross price.
+	TaxInclusive
+)
+
+// String is an implementation of fmt.Stringer.
+func (m TaxMode) String() string {
+	switch m {
+	case TaxExclusive:
+		return "TaxExclusive"
+	case TaxInclusive:
+		return "TaxInclusive"
+	}
+	return fmt.Sprintf("TaxMode(%d)", int(m))
+}
+
+// MarshalText is an implementation of encoding.TextMarshaler, e.g.
+// "exclusive".
+func (m TaxMode) MarshalText() ([]byte, error) {
+	switch m {
+	case TaxExclusive:
+		return []byte("exclusive"), nil
+	case TaxInclusive:
+		return []byte("inclusive"), nil
+	}
+	return nil, fmt.Errorf("failed to marshal tax mode %d", int(m))
+}
+
+// UnmarshalText is an implementation of encoding.TextUnmarshaler.
+func (m *TaxMode) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "exclusive":
+		*m = TaxExclusive
+	case "inclusive":
+		*m = TaxInclusive
+	default:
+		return fmt.Errorf("failed to unmarshal tax mode '%s'", text)
+	}
+	return nil
+}
+
+// TaxCategory is a code classifying the rate of a tax, e.g. for VAT.
+type TaxCategory string
+
+const (
+	TaxStandard TaxCategory = "S" // The standard rate.
+	TaxReduced  TaxCategory = "R" // A reduced rate.
+	TaxZero     TaxCategory = "Z" // The zero rate.
+	TaxExempt   TaxCategory = "E" // Exempt from tax.
+)
+
+// Tax defines how a tax is calculated. Taxes are applied to a price in order,
+// so a tax can only be charged on taxes that were applied before it. Taxes
+// applied to a price are kept separate unless they have the same ID or, if
+// they have no ID, the same jurisdiction and label.
+type Tax struct {
+	ID           string      // The unique identifier of the tax, optional.
+	Description  string      // The description of the tax, e.g. "VAT".
+	Jurisdiction string      // The country or subdivision levying the tax, e.g. "GB" or "CA-QC".
+	Category     TaxCategory // The category of the rate, optional.
+	Base         TaxBase     // The amount the tax is calculated on.
+	Mode         TaxMode     // Whether the tax is added to or included in the gross.
+	Rate         *big.Rat    // The percentage rate, e.g. 20 for 20%. Unused by per unit taxes.
+	Amount       Money       // The fixed amount per unit. Only used by per unit taxes.
+	On           []string    // The IDs or descriptions of the earlier taxes a compound tax is charged on.
+}
+
+// NetTax constructs a tax that's a percentage of the net. The percentage is
+// a decimal string, e.g. "17.5".
+func NetTax(desc string, percent string) (Tax, error) {
+	return newRateTax(desc, TaxOnNet, percent, nil)
+}
+
+// GrossTax constructs a tax that's a percentage of the net plus every earlier
+// tax. The percentage is a decimal string, e.g. "17.5".
+func GrossTax(desc string, percent string) (Tax, error) {
+	return newRateTax(desc, TaxOnGross, percent, nil)
+}
+
+// CompoundTax constructs a tax that's a percentage of the net plus the named
+// earlier taxes. The percentage is a decimal string, e.g. "9.975".
+func CompoundTax(desc string, percent string, on ...string) (Tax, error) {
+	return newRateTax(desc, TaxCompound, percent, on)
+}
+
+// UnitTax constructs a tax that's a fixed amount per unit.
+func UnitTax(desc string, amount Money) Tax {
+	return Tax{Description: desc, Base: TaxPerUnit, Amount: amount}
+}
+
+// newRateTax constructs a percentage tax.
+func newRateTax(desc string, base TaxBase, percent string, on []string) (Tax, error) {
+	r, err := parseDecimal(percent)
+	if err != nil {
+		return Tax{}, fmt.Errorf("failed to create tax '%s', %w", desc, err)
+	}
+	t := Tax{Description: desc, Base: base, Rate: r, On: on}
+	if err := t.validate(); err != nil {
+		return Tax{}, err
+	}
+	return t.clone(), nil
+}
+
+// Label returns the description of the tax with its rate and category, e.g.
+// "VAT 20% (S)". Per unit taxes don't show an amount.
+func (t Tax) Label() string {
+	var sb strings.Builder
+	sb.WriteString(t.Description)
+	if t.Base != TaxPerUnit && t.Rate != nil {
+		fmt.Fprintf(&sb, " %s%%", ratString(t.Rate))
+	}
+	if t.Category != "" {
+		fmt.Fprintf(&sb, " (%s)", t.Category)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// String is an implementation of fmt.Stringer, e.g. "VAT 20% TaxOnNet".
+func (t Tax) String() string {
+	if t.Base == TaxPerUnit {
+		return fmt.Sprintf("%s %s %s", t.Label(), t.Amount, t.Base)
+	}
+	if t.Base == TaxCompound {
+		return fmt.Sprintf("%s %s on %s", t.Label(), t.Base, strings.Join(t.On, ", "))
+	}
+	return fmt.Sprintf("%s %s", t.Label(), t.Base)
+}
+
+// MarshalJSON is an implementation of json.Marshaller.
+func (t Tax) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.toJSON())
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler. A fixed amount must
+// be a full money object, e.g. {"currency":"GBP","amount":"£0.10"}.
+func (t *Tax) UnmarshalJSON(b []byte) error {
+	var v taxJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal tax, %w", err)
+	}
+	tax, err := v.tax(Money{})
+	if err != nil {
+		return err
+	}
+	*t = tax
+	return nil
+}
+
+// validate checks the tax can be calculated.
+func (t Tax) validate() error {
+	switch t.Base {
+	case TaxOnNet, TaxOnGross, TaxCompound:
+		if t.Rate == nil {
+			return fmt.Errorf("invalid tax '%s', no rate specified", t.Description)
+		}
+	case TaxPerUnit:
+		if t.Amount.currency.Code == "" {
+			return fmt.Errorf("invalid tax '%s', no amount specified", t.Description)
+		}
+	default:
+		return fmt.Errorf("invalid tax '%s', unknown base %d", t.Description, t.Base)
+	}
+	if t.Base == TaxCompound && len(t.On) == 0 {
+		return fmt.Errorf("invalid tax '%s', compound taxes must name the taxes they're charged on", t.Description)
+	}
+	if t.Mode != TaxExclusive && t.Mode != TaxInclusive {
+		return fmt.Errorf("invalid tax '%s', unknown mode %d", t.Description, t.Mode)
+	}
+	return nil
+}
+
+// key returns the value used to decide if two taxes are the same.
+func (t Tax) key() string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return t.Jurisdiction + "|" + t.Label()
+}
+
+// appliesTo returns true if the tax is charged on the passed earlier tax.
+func (t Tax) appliesTo(v Tax) bool {
+	switch t.Base {
+	case TaxOnGross:
+		return true
+	case TaxCompound:
+		return t.names(v)
+	}
+	return false
+}
+
+// names returns true if the passed tax is named in the tax's On field, by ID
+// or description.
+func (t Tax) names(v Tax) bool {
+	for _, name := range t.On {
+		if (v.ID != "" && name == v.ID) || name == v.Description {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy of the tax that doesn't share its rate or the slice of
+// compounded taxes.
+func (t Tax) clone() Tax {
+	if t.Rate != nil {
+		t.Rate = new(big.Rat).Set(t.Rate)
+	}
+	if t.On != nil {
+		t.On = append([]string{}, t.On...)
+	}
+	return t
+}
+
+// toJSON returns the JSON representation of the tax.
+func (t Tax) toJSON() taxJSON {
+	v := taxJSON{
+		ID:           t.ID,
+		Description:  t.Description,
+		Label:        t.Label(),
+		Jurisdiction: t.Jurisdiction,
+		Category:     t.Category,
+		Base:         t.Base,
+		Mode:         t.Mode,
+		On:           t.On,
+	}
+	if t.Rate != nil {
+		v.Rate = rateString(t.Rate)
+	}
+	if t.Amount.currency.Code != "" {
+		v.Fixed, _ = t.Amount.MarshalJSON()
+	}
+	return v
+}
+
+// taxJSON is the JSON representation of a tax. The amount is only used by
+// tax lines.
+type taxJSON struct {
+	ID           string          `json:"id,omitempty"`
+	Description  string          `json:"description"`
+	Label        string          `json:"label,omitempty"`
+	Jurisdiction string          `json:"jurisdiction,omitempty"`
+	Category     TaxCategory     `json:"category,omitempty"`
+	Base         TaxBase         `json:"base"`
+	Mode         TaxMode         `json:"mode"`
+	Rate         string          `json:"rate,omitempty"`
+	Fixed        json.RawMessage `json:"fixed,omitempty"`
+	On           []string        `json:"on,omitempty"`
+	Amount       json.RawMessage `json:"amount,omitempty"`
+}
+
+// tax returns the tax the JSON represents. A bare fixed amount string is
+// parsed using the currency of the passed money object.
+func (v taxJSON) tax(currency Money) (Tax, error) {
+	t := Tax{
+		ID:           v.ID,
+		Description:  v.Description,
+		Jurisdiction: v.Jurisdiction,
+		Category:     v.Category,
+		Base:         v.Base,
+		Mode:         v.Mode,
+		On:           v.On,
+	}
+	if v.Rate != "" {
+		r, err := parseRate(v.Rate)
+		if err != nil {
+			return Tax{}, fmt.Errorf("failed to unmarshal tax '%s', %w", v.Description, err)
+		}
+		t.Rate = r
+	}
+	if len(v.Fixed) > 0 {
+		t.Amount = currency.Clone(0)
+		if err := json.Unmarshal(v.Fixed, &t.Amount); err != nil {
+			return Tax{}, fmt.Errorf("failed to unmarshal tax '%s', %w", v.Description, err)
+		}
+	}
+	return t, nil
+}
+
+// rateString returns a rate as an exact decimal string, or a fraction if the
+// rate has no exact decimal representation.
+func rateString(r *big.Rat) string {
+	str := ratString(r)
+	if v, ok := new(big.Rat).SetString(str); ok && v.Cmp(r) == 0 {
+		return str
+	}
+	return r.RatString()
+}
+
+// fractionRegex matches a fraction of integers, e.g. "50/3".
+var fractionRegex = regexp.MustCompile(`^[-+]?[0-9]+/[0-9]+$`)
+
+// parseRate parses a rate written by rateString, which is a plain decimal
+// number or a fraction of integers.
+func parseRate(str string) (*big.Rat, error) {
+	if !fractionRegex.MatchString(str) {
+		return parseDecimal(str)
+	}
+	r, ok := new(big.Rat).SetString(str)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse '%s' as a rate", str)
+	}
+	return r, nil
+}
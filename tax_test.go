@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"encoding/json"
+	"math/big"
 	"testing"
 )
 
@@ -11,11 +12,199 @@ func TestTaxJsonMarshalling(t *testing.T) {
 
 	taxes := taxes{
 		total:  t1,
-		detail: make(map[string]Money, 0),
+		detail: make(detail, 0),
 	}
 
-	taxes = taxes.add("VAT", t2)
+	taxes = taxes.add(Tax{Description: "VAT"}, t2)
 
 	bytes, _ := json.Marshal(taxes)
-	assertJSON(t, bytes, `{"total":"£13.87","detail":[{"amount":"£13.87","description":"VAT"}]}`)
+	assertJSON(t, bytes, `{"total":"£13.87","detail":[{"description":"VAT","label":"VAT","base":"net","mode":"exclusive","amount":"£13.87"}]}`)
+}
+
+func TestTaxJsonUnmarshalling(t *testing.T) {
+	t1, _ := MoneyGBP(0)
+
+	taxes := taxes{total: t1}
+	err := json.Unmarshal([]byte(`{"total":"£14.87","detail":[{"amount":"£13.87","description":"VAT"},{"amount":"£1.00","description":"Levy"}]}`), &taxes)
+	if err != nil {
+		t.Errorf("Taxes failed to unmarshal: %s", err)
+	}
+	assertMoneyValue(t, taxes.total, 1487)
+	assertMoneyValue(t, taxAmount(taxes.detail, "VAT"), 1387)
+	assertMoneyValue(t, taxAmount(taxes.detail, "Levy"), 100)
+
+	var d detail
+	err = json.Unmarshal([]byte(`[{"amount":{"currency":"GBP","amount":"£13.87"},"description":"VAT"}]`), &d)
+	if err != nil {
+		t.Errorf("Detail failed to unmarshal: %s", err)
+	}
+	assertMoneyString(t, taxAmount(d, "VAT"), "GBP", "£13.87")
+
+	err = json.Unmarshal([]byte(`[{"amount":"£13.87","description":"VAT"}]`), &d)
+	if err == nil {
+		t.Errorf("Detail failed to error on an amount without a currency")
+	}
+}
+
+func TestTaxDefinitions(t *testing.T) {
+	gst, err := NetTax("GST", "5")
+	assert(t, err == nil)
+	assert(t, gst.Rate.Cmp(big.NewRat(5, 1)) == 0)
+	assert(t, gst.String() == "GST 5% TaxOnNet")
+
+	qst, err := CompoundTax("QST", "9.975", "GST")
+	assert(t, err == nil)
+	assert(t, qst.String() == "QST 9.975% TaxCompound on GST")
+
+	vat, err := GrossTax("VAT", "17.5")
+	assert(t, err == nil)
+	assert(t, vat.String() == "VAT 17.5% TaxOnGross")
+
+	m, _ := MoneyGBP(10)
+	assert(t, UnitTax("Deposit", m).String() == "Deposit £0.10 TaxPerUnit")
+	assert(t, TaxBase(9).String() == "TaxBase(9)")
+
+	_, err = NetTax("GST", "five")
+	assert(t, err != nil)
+	_, err = CompoundTax("QST", "9.975")
+	assert(t, err != nil)
+	assert(t, Tax{Description: "VAT"}.validate() != nil)
+	assert(t, Tax{Description: "Levy", Base: TaxPerUnit}.validate() != nil)
+	assert(t, Tax{Description: "VAT", Base: 9, Rate: big.NewRat(20, 1)}.validate() != nil)
+}
+
+// taxAmount returns the amount of the first tax with the passed description.
+func taxAmount(d detail, desc string) Money {
+	for _, l := range d {
+		if l.Tax.Description == desc {
+			return l.Amount
+		}
+	}
+	return Money{}
+}
+
+func TestTaxLabels(t *testing.T) {
+	vat, _ := NetTax("VAT", "20")
+	assert(t, vat.Label() == "VAT 20%")
+
+	vat.Category = TaxStandard
+	assert(t, vat.Label() == "VAT 20% (S)")
+	assert(t, vat.String() == "VAT 20% (S) TaxOnNet")
+
+	m, _ := MoneyGBP(10)
+	deposit := UnitTax("Deposit", m)
+	deposit.Category = TaxZero
+	assert(t, deposit.Label() == "Deposit (Z)")
+
+	third := Tax{Description: "Third", Rate: big.NewRat(100, 3)}
+	assert(t, rateString(third.Rate) == "100/3")
+
+	b, err := json.Marshal(third)
+	assert(t, err == nil)
+	var decoded Tax
+	assert(t, json.Unmarshal(b, &decoded) == nil)
+	assert(t, decoded.Rate.Cmp(third.Rate) == 0)
+}
+
+func TestTaxesWithTheSameDescriptionAreKeptSeparate(t *testing.T) {
+	standard, _ := NetTax("VAT", "20")
+	standard.Category = TaxStandard
+	reduced, _ := NetTax("VAT", "5")
+	reduced.Category = TaxReduced
+
+	p1, _ := PriceFromSubunits("GBP", 1000, nil)
+	assert(t, p1.AddTaxes(standard) == nil)
+	p2, _ := PriceFromSubunits("GBP", 400, nil)
+	assert(t, p2.AddTaxes(reduced) == nil)
+	p3, _ := PriceFromSubunits("GBP", 500, nil)
+	assert(t, p3.AddTaxes(standard) == nil)
+
+	p := p1.Add(p2).Add(p3)
+	lines := p.TaxLines()
+	assert(t, len(lines) == 2)
+	assert(t, lines[0].Tax.Label() == "VAT 20% (S)")
+	assertMoneyValue(t, lines[0].Amount, 300)
+	assert(t, lines[1].Tax.Label() == "VAT 5% (R)")
+	assertMoneyValue(t, lines[1].Amount, 20)
+
+	// Taxes with an ID are merged by ID regardless of their description.
+	a := standard
+	a.ID = "gb-vat-s"
+	b := standard
+	b.ID = "gb-vat-s"
+	b.Description = "Value added tax"
+	p1, _ = PriceFromSubunits("GBP", 1000, nil)
+	assert(t, p1.AddTaxes(a) == nil)
+	p2, _ = PriceFromSubunits("GBP", 1000, nil)
+	assert(t, p2.AddTaxes(b) == nil)
+	assert(t, len(p1.Add(p2).TaxLines()) == 1)
+
+	// Taxes in different jurisdictions are kept separate.
+	b = standard
+	b.Jurisdiction = "FR"
+	p2, _ = PriceFromSubunits("GBP", 1000, nil)
+	assert(t, p2.AddTaxes(b) == nil)
+	assert(t, len(p1.Add(p2).TaxLines()) == 2)
+}
+
+func TestTaxDetailJsonRoundTrip(t *testing.T) {
+	duty, _ := NetTax("Duty", "12")
+	duty.ID = "duty"
+	vat, _ := CompoundTax("VAT", "20", "duty")
+	vat.ID = "gb-vat-s"
+	vat.Jurisdiction = "GB"
+	vat.Category = TaxStandard
+	m, _ := MoneyGBP(10)
+	deposit := UnitTax("Deposit", m)
+
+	p, _ := PriceFromSubunits("GBP", 5000, nil)
+	assert(t, p.AddTaxes(duty, vat, deposit) == nil)
+
+	bytes, _ := json.Marshal(p)
+	assertJSON(t, bytes, `{"currency":"GBP","gross":"£67.30","net":"£50.00","tax":{"total":"£17.30","detail":[{"id":"duty","description":"Duty","label":"Duty 12%","base":"net","mode":"exclusive","rate":"12","amount":"£6.00"},{"id":"gb-vat-s","description":"VAT","label":"VAT 20% (S)","jurisdiction":"GB","category":"S","base":"compound","mode":"exclusive","rate":"20","on":["duty"],"amount":"£11.20"},{"description":"Deposit","label":"Deposit","base":"unit","mode":"exclusive","fixed":{"currency":"GBP","amount":"£0.10"},"amount":"£0.10"}]}}`)
+
+	var r Price
+	assert(t, json.Unmarshal(bytes, &r) == nil)
+	ts := r.Taxes()
+	assert(t, len(ts) == 3)
+	assert(t, ts[1].String() == "VAT 20% (S) TaxCompound on duty")
+	assert(t, ts[1].ID == "gb-vat-s" && ts[1].Jurisdiction == "GB")
+	assert(t, ts[2].String() == "Deposit £0.10 TaxPerUnit")
+
+	again, _ := json.Marshal(r)
+	assertJSON(t, again, string(bytes))
+}
+
+func TestTaxJsonMarshallingErrors(t *testing.T) {
+	var tax Tax
+	assert(t, json.Unmarshal([]byte(`{"description":"VAT","base":"sideways"}`), &tax) != nil)
+	assert(t, json.Unmarshal([]byte(`{"description":"VAT","mode":"sometimes"}`), &tax) != nil)
+	assert(t, json.Unmarshal([]byte(`{"description":"VAT","rate":"twenty"}`), &tax) != nil)
+	assert(t, json.Unmarshal([]byte(`{"description":"VAT","fixed":"£1.00"}`), &tax) != nil)
+
+	assert(t, json.Unmarshal([]byte(`{"description":"VAT","rate":"17.5","base":"gross","mode":"inclusive","category":"S"}`), &tax) == nil)
+	assert(t, tax.String() == "VAT 17.5% (S) TaxOnGross")
+	assert(t, tax.Mode == TaxInclusive)
+
+	_, err := json.Marshal(Tax{Base: 9})
+	assert(t, err != nil)
+}
+
+func TestApplyTaxes(t *testing.T) {
+	vat, _ := NetTax("VAT", "20")
+	vat.Mode = TaxInclusive
+	tip, _ := GrossTax("Service", "10")
+
+	p, _ := PriceFromSubunits("GBP", 1200, nil)
+	assert(t, p.ApplyTaxes(tip, vat) == nil)
+	assertMoneyValue(t, p.Net(), 1000)
+	assertMoneyValue(t, p.Gross(), 1320)
+
+	ts := p.Taxes()
+	assert(t, ts[0].Description == "VAT" && ts[0].Mode == TaxInclusive)
+	assert(t, ts[1].Description == "Service" && ts[1].Mode == TaxExclusive)
+
+	bad, _ := CompoundTax("Bad", "1", "Nothing")
+	assert(t, p.ApplyTaxes(vat, bad) != nil)
+	assert(t, len(p.Taxes()) == 2)
 }
@@ -0,0 +1,374 @@
+package mongo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// taxRateDate is the layout of the dates used by tax rate files.
+const taxRateDate = "2006-01-02"
+
+// TaxRate is the definition of a tax that's effective for a range of dates.
+// The tax's jurisdiction and category identify where and to what the rate
+// applies.
+type TaxRate struct {
+	Tax  Tax       // The definition of the tax.
+	From time.Time // The first date the rate is effective.
+	To   time.Time // The last date the rate is effective, or zero if it has no end.
+}
+
+// String is an implementation of fmt.Stringer, e.g.
+// "GB VAT 20% (S) TaxOnNet from 2011-01-04".
+func (r TaxRate) String() string {
+	str := fmt.Sprintf("%s %s from %s", r.Tax.Jurisdiction, r.Tax, r.From.Format(taxRateDate))
+	if !r.To.IsZero() {
+		str += " to " + r.To.Format(taxRateDate)
+	}
+	return str
+}
+
+// EffectiveOn returns true if the rate is effective on the passed date.
+func (r TaxRate) EffectiveOn(date time.Time) bool {
+	date = day(date)
+	return !date.Before(r.From) && (r.To.IsZero() || !date.After(r.To))
+}
+
+// MarshalJSON is an implementation of json.Marshaller. The rate is encoded as
+// a tax with "from" and "to" dates.
+func (r TaxRate) MarshalJSON() ([]byte, error) {
+	v := taxRateJSON{taxJSON: r.Tax.toJSON(), From: r.From.Format(taxRateDate)}
+	if !r.To.IsZero() {
+		v.To = r.To.Format(taxRateDate)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler.
+func (r *TaxRate) UnmarshalJSON(b []byte) error {
+	var v taxRateJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal tax rate, %w", err)
+	}
+	tax, err := v.tax(Money{})
+	if err != nil {
+		return err
+	}
+	rate, err := newTaxRate(tax, v.From, v.To)
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
+
+// taxRateJSON is the JSON representation of a tax rate.
+type taxRateJSON struct {
+	taxJSON
+	From string `json:"from"`
+	To   string `json:"to,omitempty"`
+}
+
+// newTaxRate constructs a tax rate from dates expressed as strings. An empty
+// to date means the rate has no end.
+func newTaxRate(tax Tax, from, to string) (TaxRate, error) {
+	r := TaxRate{Tax: tax}
+	var err error
+	if r.From, err = time.Parse(taxRateDate, strings.TrimSpace(from)); err != nil {
+		return TaxRate{}, fmt.Errorf("invalid tax rate '%s', '%s' is not a valid date", tax.Description, from)
+	}
+	if strings.TrimSpace(to) != "" {
+		if r.To, err = time.Parse(taxRateDate, strings.TrimSpace(to)); err != nil {
+			return TaxRate{}, fmt.Errorf("invalid tax rate '%s', '%s' is not a valid date", tax.Description, to)
+		}
+	}
+	return r, nil
+}
+
+// validate checks the rate can be added to a tax rate table.
+func (r TaxRate) validate() error {
+	if err := r.Tax.validate(); err != nil {
+		return err
+	}
+	if r.Tax.Jurisdiction == "" {
+		return fmt.Errorf("invalid tax rate '%s', no jurisdiction specified", r.Tax.Description)
+	}
+	if r.From.IsZero() {
+		return fmt.Errorf("invalid tax rate '%s', no effective date specified", r.Tax.Description)
+	}
+	if !r.To.IsZero() && day(r.To).Before(day(r.From)) {
+		return fmt.Errorf("invalid tax rate '%s', it ends before it starts", r.Tax.Description)
+	}
+	return nil
+}
+
+// overlaps returns true if both rates define the same tax and are effective
+// on at least one of the same dates.
+func (r TaxRate) overlaps(v TaxRate) bool {
+	if r.Tax.Jurisdiction != v.Tax.Jurisdiction || r.Tax.Category != v.Tax.Category || taxIdentity(r.Tax) != taxIdentity(v.Tax) {
+		return false
+	}
+	return (r.To.IsZero() || !v.From.After(r.To)) && (v.To.IsZero() || !r.From.After(v.To))
+}
+
+// taxIdentity returns the value used to decide if two rates define the same
+// tax, which is its ID or if it has none, its description.
+func taxIdentity(t Tax) string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return t.Description
+}
+
+// TaxRates is a table of tax rates by jurisdiction and category, each with the
+// dates it's effective. Jurisdictions are ISO 3166 country codes or ISO 3166-2
+// subdivision codes, e.g. "CA" or "CA-QC". Looking up a subdivision returns
+// the rates of its country too, unless the subdivision replaces a tax with one
+// that has the same ID or description. It's safe for concurrent use.
+type TaxRates struct {
+	mu    sync.RWMutex
+	rates map[string][]TaxRate // Keyed by jurisdiction and sorted by date.
+}
+
+// NewTaxRates constructs a new tax rate table containing the passed rates.
+func NewTaxRates(rates ...TaxRate) (*TaxRates, error) {
+	t := &TaxRates{rates: make(map[string][]TaxRate)}
+	for _, r := range rates {
+		if err := t.Add(r); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Add adds a rate to the table. Only the dates of the rate are used, the time
+// of day is ignored. An error is returned if the rate is effective on the same
+// date as another rate for the same tax.
+func (t *TaxRates) Add(r TaxRate) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	r.Tax = r.Tax.clone()
+	r.From = day(r.From)
+	if !r.To.IsZero() {
+		r.To = day(r.To)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rates == nil {
+		t.rates = make(map[string][]TaxRate)
+	}
+
+	series := t.rates[r.Tax.Jurisdiction]
+	for _, existing := range series {
+		if existing.overlaps(r) {
+			return fmt.Errorf("invalid tax rate %s, it overlaps %s", r, existing)
+		}
+	}
+	i := sort.Search(len(series), func(i int) bool {
+		return series[i].From.After(r.From)
+	})
+	series = append(series, TaxRate{})
+	copy(series[i+1:], series[i:])
+	series[i] = r
+	t.rates[r.Tax.Jurisdiction] = series
+	return nil
+}
+
+// Taxes returns the taxes of a category that were effective in a jurisdiction
+// on the passed date, country taxes first. An error wrapping ErrNoTaxRate is
+// returned if there are none.
+func (t *TaxRates) Taxes(jurisdiction string, category TaxCategory, date time.Time) ([]Tax, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var taxes []Tax
+	jurisdictions := []string{jurisdiction}
+	if country, _, ok := strings.Cut(jurisdiction, "-"); ok {
+		jurisdictions = []string{country, jurisdiction}
+	}
+	for _, j := range jurisdictions {
+		for _, r := range t.rates[j] {
+			if r.Tax.Category != category || !r.EffectiveOn(date) {
+				continue
+			}
+			taxes = append(removeTax(taxes, taxIdentity(r.Tax)), r.Tax.clone())
+		}
+	}
+
+	if len(taxes) == 0 {
+		return nil, fmt.Errorf("no %s tax rate in category '%s' for %s, %w", jurisdiction, category, date.Format(taxRateDate), ErrNoTaxRate)
+	}
+	return taxes, nil
+}
+
+// Apply adds the taxes of a category that were effective in a jurisdiction on
+// the passed date to the price according to their mode. The price is left
+// unmodified if an error is returned.
+func (t *TaxRates) Apply(p *Price, jurisdiction string, category TaxCategory, date time.Time) error {
+	taxes, err := t.Taxes(jurisdiction, category, date)
+	if err != nil {
+		return fmt.Errorf("failed to apply taxes, %w", err)
+	}
+	return p.ApplyTaxes(taxes...)
+}
+
+// Rates returns every rate in the table sorted by jurisdiction and date.
+func (t *TaxRates) Rates() []TaxRate {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	jurisdictions := make([]string, 0, len(t.rates))
+	for j := range t.rates {
+		jurisdictions = append(jurisdictions, j)
+	}
+	sort.Strings(jurisdictions)
+
+	var rates []TaxRate
+	for _, j := range jurisdictions {
+		for _, r := range t.rates[j] {
+			r.Tax = r.Tax.clone()
+			rates = append(rates, r)
+		}
+	}
+	return rates
+}
+
+// removeTax returns the taxes without the one with the passed identity.
+func removeTax(taxes []Tax, id string) []Tax {
+	result := taxes[:0]
+	for _, t := range taxes {
+		if taxIdentity(t) != id {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// LoadTaxRatesJSON reads tax rates from a JSON file on disk.
+func LoadTaxRatesJSON(path string) ([]TaxRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tax rates, %w", err)
+	}
+	defer f.Close()
+	return ReadTaxRatesJSON(f)
+}
+
+// LoadTaxRatesCSV reads tax rates from a CSV file on disk.
+func LoadTaxRatesCSV(path string) ([]TaxRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tax rates, %w", err)
+	}
+	defer f.Close()
+	return ReadTaxRatesCSV(f)
+}
+
+// ReadTaxRatesJSON reads tax rates from a JSON array of objects, e.g.
+// {"jurisdiction":"GB","category":"S","description":"VAT","rate":"20","from":"2011-01-04"}.
+// Each object takes the fields of a tax plus "from" and "to" dates, with "to"
+// omitted if the rate has no end.
+func ReadTaxRatesJSON(r io.Reader) ([]TaxRate, error) {
+	var rates []TaxRate
+	if err := json.NewDecoder(r).Decode(&rates); err != nil {
+		return nil, fmt.Errorf("failed to read tax rates, %w", err)
+	}
+	for _, rate := range rates {
+		if err := rate.validate(); err != nil {
+			return nil, fmt.Errorf("failed to read tax rates, %w", err)
+		}
+	}
+	return rates, nil
+}
+
+// ReadTaxRatesCSV reads tax rates from CSV. The header names the columns,
+// which can be in any order. The jurisdiction, description and from columns
+// are required. The others are id, category, rate, fixed, base, mode, on and
+// to. Fixed amounts must include a currency symbol or code, e.g. "£0.10", and
+// compound taxes separate the taxes they're charged on with semicolons.
+func ReadTaxRatesCSV(r io.Reader) ([]TaxRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tax rates, %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"jurisdiction", "description", "from"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("failed to read tax rates, no '%s' column", name)
+		}
+	}
+
+	var rates []TaxRate
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tax rates, %w", err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		v := taxJSON{
+			ID:           field("id"),
+			Description:  field("description"),
+			Jurisdiction: field("jurisdiction"),
+			Category:     TaxCategory(field("category")),
+			Rate:         field("rate"),
+		}
+		if str := field("base"); str != "" {
+			if err := v.Base.UnmarshalText([]byte(str)); err != nil {
+				return nil, fmt.Errorf("failed to read tax rates, %w", err)
+			}
+		}
+		if str := field("mode"); str != "" {
+			if err := v.Mode.UnmarshalText([]byte(str)); err != nil {
+				return nil, fmt.Errorf("failed to read tax rates, %w", err)
+			}
+		}
+		if str := field("on"); str != "" {
+			for _, name := range strings.Split(str, ";") {
+				v.On = append(v.On, strings.TrimSpace(name))
+			}
+		}
+		tax, err := v.tax(Money{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tax rates, %w", err)
+		}
+		if str := field("fixed"); str != "" {
+			if tax.Amount, err = ParseMoney(str); err != nil {
+				return nil, fmt.Errorf("failed to read tax rates, %w", err)
+			}
+		}
+
+		rate, err := newTaxRate(tax, field("from"), field("to"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tax rates, %w", err)
+		}
+		if err := rate.validate(); err != nil {
+			return nil, fmt.Errorf("failed to read tax rates, %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
@@ -0,0 +1,191 @@
+package mongo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const ukVATCSV = `jurisdiction,category,id,description,rate,base,mode,from,to,fixed
+GB,S,gb-vat,VAT,17.5,net,inclusive,1991-04-01,2008-11-30
+GB,S,gb-vat,VAT,15,net,inclusive,2008-12-01,2009-12-31
+GB,S,gb-vat,VAT,17.5,net,inclusive,2010-01-01,2011-01-03
+GB,S,gb-vat,VAT,20,net,inclusive,2011-01-04,
+GB,R,gb-vat,VAT,5,net,inclusive,1997-09-01,
+GB,Z,gb-deposit,Deposit,,unit,inclusive,2025-10-01,,£0.20
+`
+
+const canadaJSON = `[
+	{"jurisdiction":"CA","category":"S","id":"ca-gst","description":"GST","rate":"5","base":"net","from":"2008-01-01"},
+	{"jurisdiction":"CA-QC","category":"S","id":"qc-qst","description":"QST","rate":"9.5","base":"compound","on":["ca-gst"],"from":"2012-01-01","to":"2012-12-31"},
+	{"jurisdiction":"CA-QC","category":"S","id":"qc-qst","description":"QST","rate":"9.975","base":"net","from":"2013-01-01"},
+	{"jurisdiction":"CA-ON","category":"S","id":"ca-gst","description":"HST","rate":"13","from":"2010-07-01"}
+]`
+
+func TestReadTaxRatesCSV(t *testing.T) {
+	rates, err := ReadTaxRatesCSV(strings.NewReader(ukVATCSV))
+	if err != nil {
+		t.Fatalf("ReadTaxRatesCSV failed: %s", err)
+	}
+	assert(t, len(rates) == 6)
+	assert(t, rates[0].String() == "GB VAT 17.5% (S) TaxOnNet from 1991-04-01 to 2008-11-30")
+	assert(t, rates[3].String() == "GB VAT 20% (S) TaxOnNet from 2011-01-04")
+	assert(t, rates[3].Tax.Mode == TaxInclusive)
+	assert(t, rates[5].String() == "GB Deposit (Z) £0.20 TaxPerUnit from 2025-10-01")
+
+	_, err = ReadTaxRatesCSV(strings.NewReader("jurisdiction,description\nGB,VAT\n"))
+	assert(t, err != nil)
+	_, err = ReadTaxRatesCSV(strings.NewReader("jurisdiction,description,rate,from\nGB,VAT,20,04/01/2011\n"))
+	assert(t, err != nil)
+	_, err = ReadTaxRatesCSV(strings.NewReader("jurisdiction,description,rate,from,to\nGB,VAT,20,2011-01-04,2010-01-01\n"))
+	assert(t, err != nil)
+	_, err = ReadTaxRatesCSV(strings.NewReader("jurisdiction,description,rate,from,base\nGB,VAT,20,2011-01-04,sideways\n"))
+	assert(t, err != nil)
+	_, err = ReadTaxRatesCSV(strings.NewReader("jurisdiction,description,from\nGB,VAT,2011-01-04\n"))
+	assert(t, err != nil)
+	_, err = ReadTaxRatesCSV(strings.NewReader("jurisdiction,description,rate,from\n,VAT,20,2011-01-04\n"))
+	assert(t, err != nil)
+}
+
+func TestReadTaxRatesJSON(t *testing.T) {
+	rates, err := ReadTaxRatesJSON(strings.NewReader(canadaJSON))
+	if err != nil {
+		t.Fatalf("ReadTaxRatesJSON failed: %s", err)
+	}
+	assert(t, len(rates) == 4)
+	assert(t, rates[1].String() == "CA-QC QST 9.5% (S) TaxCompound on ca-gst from 2012-01-01 to 2012-12-31")
+
+	bytes, _ := json.Marshal(rates[1])
+	assertJSON(t, bytes, `{"id":"qc-qst","description":"QST","label":"QST 9.5% (S)","jurisdiction":"CA-QC","category":"S","base":"compound","mode":"exclusive","rate":"9.5","on":["ca-gst"],"from":"2012-01-01","to":"2012-12-31"}`)
+
+	var r TaxRate
+	assert(t, json.Unmarshal(bytes, &r) == nil)
+	assert(t, r.String() == rates[1].String())
+
+	_, err = ReadTaxRatesJSON(strings.NewReader(`[{"jurisdiction":"CA","description":"GST","rate":"5","from":"01/01/2008"}]`))
+	assert(t, err != nil)
+	_, err = ReadTaxRatesJSON(strings.NewReader(`[{"jurisdiction":"CA","description":"GST","from":"2008-01-01"}]`))
+	assert(t, err != nil)
+	_, err = ReadTaxRatesJSON(strings.NewReader(`{}`))
+	assert(t, err != nil)
+}
+
+func TestLoadTaxRates(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "rates.csv")
+	jsonPath := filepath.Join(dir, "rates.json")
+	os.WriteFile(csvPath, []byte(ukVATCSV), 0o600)
+	os.WriteFile(jsonPath, []byte(canadaJSON), 0o600)
+
+	rates, err := LoadTaxRatesCSV(csvPath)
+	assert(t, err == nil && len(rates) == 6)
+	rates, err = LoadTaxRatesJSON(jsonPath)
+	assert(t, err == nil && len(rates) == 4)
+
+	_, err = LoadTaxRatesCSV(filepath.Join(dir, "missing.csv"))
+	assert(t, err != nil)
+	_, err = LoadTaxRatesJSON(filepath.Join(dir, "missing.json"))
+	assert(t, err != nil)
+}
+
+func TestTaxRatesLookup(t *testing.T) {
+	uk, _ := ReadTaxRatesCSV(strings.NewReader(ukVATCSV))
+	ca, _ := ReadTaxRatesJSON(strings.NewReader(canadaJSON))
+	table, err := NewTaxRates(append(uk, ca...)...)
+	if err != nil {
+		t.Fatalf("NewTaxRates failed: %s", err)
+	}
+
+	taxes, err := table.Taxes("GB", TaxStandard, date(2009, 6, 1))
+	assert(t, err == nil && len(taxes) == 1)
+	assert(t, taxes[0].Label() == "VAT 15% (S)")
+
+	taxes, _ = table.Taxes("GB", TaxStandard, date(2011, 1, 3))
+	assert(t, taxes[0].Label() == "VAT 17.5% (S)")
+	taxes, _ = table.Taxes("GB", TaxStandard, date(2011, 1, 4))
+	assert(t, taxes[0].Label() == "VAT 20% (S)")
+	taxes, _ = table.Taxes("GB", TaxReduced, date(2024, 1, 1))
+	assert(t, taxes[0].Label() == "VAT 5% (R)")
+
+	// Subdivisions fall back to the rates of their country.
+	taxes, _ = table.Taxes("GB-SCT", TaxStandard, date(2024, 1, 1))
+	assert(t, len(taxes) == 1 && taxes[0].Label() == "VAT 20% (S)")
+
+	// Subdivision taxes are added to the country's taxes.
+	taxes, _ = table.Taxes("CA-QC", TaxStandard, date(2012, 6, 1))
+	assert(t, len(taxes) == 2)
+	assert(t, taxes[0].Label() == "GST 5% (S)")
+	assert(t, taxes[1].Label() == "QST 9.5% (S)")
+
+	// Unless they replace one.
+	taxes, _ = table.Taxes("CA-ON", TaxStandard, date(2024, 1, 1))
+	assert(t, len(taxes) == 1 && taxes[0].Label() == "HST 13% (S)")
+
+	_, err = table.Taxes("GB", TaxStandard, date(1990, 1, 1))
+	assert(t, errors.Is(err, ErrNoTaxRate))
+	_, err = table.Taxes("FR", TaxStandard, date(2024, 1, 1))
+	assert(t, errors.Is(err, ErrNoTaxRate))
+	_, err = table.Taxes("GB", TaxExempt, date(2024, 1, 1))
+	assert(t, errors.Is(err, ErrNoTaxRate))
+
+	assert(t, len(table.Rates()) == 10)
+	assert(t, table.Rates()[0].Tax.Jurisdiction == "CA")
+}
+
+func TestTaxRatesAdd(t *testing.T) {
+	uk, _ := ReadTaxRatesCSV(strings.NewReader(ukVATCSV))
+	table, _ := NewTaxRates(uk...)
+
+	overlap, _ := NetTax("VAT", "21")
+	overlap.ID = "gb-vat"
+	overlap.Jurisdiction = "GB"
+	overlap.Category = TaxStandard
+	assert(t, table.Add(TaxRate{Tax: overlap, From: date(2010, 6, 1), To: date(2010, 6, 30)}) != nil)
+	assert(t, table.Add(TaxRate{Tax: overlap, From: date(2030, 1, 1)}) != nil)
+	assert(t, table.Add(TaxRate{Tax: overlap, From: date(1980, 1, 1), To: date(1991, 4, 1)}) != nil)
+	assert(t, table.Add(TaxRate{Tax: overlap, From: date(1980, 1, 1), To: date(1991, 3, 31)}) == nil)
+
+	assert(t, table.Add(TaxRate{Tax: overlap}) != nil)
+	assert(t, table.Add(TaxRate{Tax: Tax{Description: "VAT", Jurisdiction: "GB"}, From: date(1980, 1, 1)}) != nil)
+
+	_, err := NewTaxRates(TaxRate{Tax: overlap, From: date(2000, 1, 1)}, TaxRate{Tax: overlap, From: date(2001, 1, 1)})
+	assert(t, err != nil)
+}
+
+func TestTaxRatesApply(t *testing.T) {
+	uk, _ := ReadTaxRatesCSV(strings.NewReader(ukVATCSV))
+	ca, _ := ReadTaxRatesJSON(strings.NewReader(canadaJSON))
+	table, _ := NewTaxRates(append(uk, ca...)...)
+
+	// A historical order recomputes exactly.
+	legacy, _ := PriceFromSubunits("GBP", 1055, nil)
+	legacy.IncludeTaxPercent(17.5, "VAT")
+
+	p, _ := PriceFromSubunits("GBP", 1055, nil)
+	assert(t, table.Apply(&p, "GB", TaxStandard, date(2010, 6, 1)) == nil)
+	assertMoneyValue(t, p.Net(), legacy.Net().Value())
+	assertMoneyValue(t, p.Tax(), legacy.Tax().Value())
+
+	p, _ = PriceFromSubunits("GBP", 1200, nil)
+	assert(t, table.Apply(&p, "GB", TaxStandard, date(2024, 1, 1)) == nil)
+	assertMoneyValue(t, p.Net(), 1000)
+	assertMoneyValue(t, p.Gross(), 1200)
+
+	// The 2012 compound QST and the 2013 QST on the net give the same tax.
+	for _, d := range []int{2012, 2013} {
+		p, _ = PriceFromSubunits("CAD", 20000, nil)
+		assert(t, table.Apply(&p, "CA-QC", TaxStandard, date(d, 6, 1)) == nil)
+		assertMoneyValue(t, p.Gross(), 22995)
+		lines := p.TaxLines()
+		assertMoneyValue(t, lines[0].Amount, 1000)
+		assertMoneyValue(t, lines[1].Amount, 1995)
+	}
+
+	p, _ = PriceFromSubunits("GBP", 1200, nil)
+	err := table.Apply(&p, "GB", TaxStandard, date(1990, 1, 1))
+	assert(t, errors.Is(err, ErrNoTaxRate))
+	assert(t, len(p.Taxes()) == 0)
+}